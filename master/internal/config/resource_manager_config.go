@@ -96,6 +96,7 @@ func (a AgentResourceManagerConfig) Validate() []error {
 // KubernetesResourceManagerConfig hosts configuration fields for the kubernetes resource manager.
 type KubernetesResourceManagerConfig struct {
 	Namespace                string                             `json:"namespace"`
+	AllowNamespaceCreation   bool                               `json:"allow_namespace_creation"`
 	MaxSlotsPerPod           int                                `json:"max_slots_per_pod"`
 	MasterServiceName        string                             `json:"master_service_name"`
 	LeaveKubernetesResources bool                               `json:"leave_kubernetes_resources"`
@@ -103,11 +104,24 @@ type KubernetesResourceManagerConfig struct {
 	SlotType                 device.Type                        `json:"slot_type"`
 	SlotResourceRequests     kubernetes.PodSlotResourceRequests `json:"slot_resource_requests"`
 	Fluent                   kubernetes.FluentConfig            `json:"fluent"`
+	CreationRetry            kubernetes.PodCreationRetryConfig  `json:"creation_retry"`
+	GPUTypeNodeLabel         string                             `json:"gpu_type_node_label"`
+	PodDeletionGracePeriod   int64                              `json:"pod_deletion_grace_period_seconds"`
+	// PodInitTimeout is how long a pod may sit initializing without an init container
+	// completing before it is treated as stuck and killed. Zero disables the timeout.
+	PodInitTimeout int64 `json:"pod_init_timeout_seconds"`
+	// MaxPodPendingTimeout is how long a pod may remain unschedulable before it is
+	// killed and its task fails with the scheduler's reason. Tasks may override it
+	// through their spec; zero disables the timeout.
+	MaxPodPendingTimeout int64 `json:"max_pod_pending_seconds"`
 }
 
 var defaultKubernetesResourceManagerConfig = KubernetesResourceManagerConfig{
-	SlotType: device.CUDA, // default to CUDA-backed slots.
-	Fluent:   kubernetes.DefaultFluentConfig,
+	SlotType:               device.CUDA, // default to CUDA-backed slots.
+	Fluent:                 kubernetes.DefaultFluentConfig,
+	CreationRetry:          kubernetes.DefaultPodCreationRetryConfig,
+	GPUTypeNodeLabel:       kubernetes.DefaultGPUTypeNodeLabel,
+	PodDeletionGracePeriod: kubernetes.DefaultPodDeletionGracePeriodSeconds,
 }
 
 // GetPreemption returns whether the RM is set to preempt.
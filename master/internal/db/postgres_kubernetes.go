@@ -0,0 +1,36 @@
+package db
+
+import (
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddTaskPod records the kubernetes pod running a task container, so the pod-to-task
+// mapping can be recovered if the master restarts while the pod is still active.
+func (db *PgDB) AddTaskPod(t *model.TaskPod) error {
+	return db.namedExecOne(`
+INSERT INTO task_pods (pod_name, namespace, container_id, allocation_id, rank, slots, log_context)
+VALUES (:pod_name, :namespace, :container_id, :allocation_id, :rank, :slots, :log_context)
+ON CONFLICT (pod_name) DO UPDATE SET
+	namespace = EXCLUDED.namespace,
+	container_id = EXCLUDED.container_id,
+	allocation_id = EXCLUDED.allocation_id,
+	rank = EXCLUDED.rank,
+	slots = EXCLUDED.slots,
+	log_context = EXCLUDED.log_context
+`, t)
+}
+
+// DeleteTaskPod removes the record of a pod once it reaches a terminal state.
+func (db *PgDB) DeleteTaskPod(podName string) error {
+	_, err := db.sql.Exec(`DELETE FROM task_pods WHERE pod_name = $1`, podName)
+	return err
+}
+
+// TaskPods returns the pods that were recorded as active when the master last stopped.
+func (db *PgDB) TaskPods() ([]*model.TaskPod, error) {
+	var pods []*model.TaskPod
+	if err := db.queryRows(`SELECT * FROM task_pods`, &pods); err != nil {
+		return nil, err
+	}
+	return pods, nil
+}
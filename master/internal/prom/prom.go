@@ -1,6 +1,8 @@
 package prom
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
@@ -47,6 +49,27 @@ Exposes mapping of Determined's container ID to the GPU UUID/device ID as given
 `,
 	}, []string{"gpu_uuid", "container_id"})
 
+	podQueueWaitSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: "det",
+		Name:      "pod_queue_wait_seconds",
+		Help:      "time task pods spend between submission and being scheduled onto a node",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"node_pool"})
+
+	podImagePullSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: "det",
+		Name:      "pod_image_pull_seconds",
+		Help:      "time task pods spend pulling their images",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"node_pool"})
+
+	podStartupSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: "det",
+		Name:      "pod_startup_seconds",
+		Help:      "time task pods spend between submission and running",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"node_pool"})
+
 	// DetStateMetrics is a prometheus registry containing all exported user-facing metrics.
 	DetStateMetrics = prometheus.NewRegistry()
 )
@@ -77,6 +100,25 @@ func init() { //nolint: gochecknoinits
 	DetStateMetrics.MustRegister(gpuUUIDToContainerID)
 	DetStateMetrics.MustRegister(experimentIDToLabels)
 	DetStateMetrics.MustRegister(allocationIDToTask)
+	DetStateMetrics.MustRegister(podQueueWaitSeconds)
+	DetStateMetrics.MustRegister(podImagePullSeconds)
+	DetStateMetrics.MustRegister(podStartupSeconds)
+}
+
+// ObservePodQueueWait records how long a task pod waited between submission and being
+// scheduled onto a node in the given node pool.
+func ObservePodQueueWait(nodePool string, d time.Duration) {
+	podQueueWaitSeconds.WithLabelValues(nodePool).Observe(d.Seconds())
+}
+
+// ObservePodImagePull records how long a task pod spent pulling its images.
+func ObservePodImagePull(nodePool string, d time.Duration) {
+	podImagePullSeconds.WithLabelValues(nodePool).Observe(d.Seconds())
+}
+
+// ObservePodStartup records how long a task pod took from submission to running.
+func ObservePodStartup(nodePool string, d time.Duration) {
+	podStartupSeconds.WithLabelValues(nodePool).Observe(d.Seconds())
 }
 
 // AssociateAllocationContainer associates an allocation with its container ID.
@@ -28,6 +28,9 @@ type (
 type (
 	podStatusUpdate struct {
 		updatedPod *k8sV1.Pod
+		// deleted is set when the watch reported the pod as deleted, so the pods actor
+		// can maintain its watch-derived cache of cluster pods without listing them.
+		deleted bool
 	}
 )
 
@@ -102,12 +105,14 @@ func (i *informer) startInformer(ctx *actor.Context) {
 			continue
 		}
 
-		if pod.Namespace != i.namespace {
+		if i.namespace != metaV1.NamespaceAll && pod.Namespace != i.namespace {
 			continue
 		}
 
 		ctx.Log().Debugf("informer got new pod event for pod: %s %s", pod.Name, pod.Status.Phase)
-		ctx.Tell(i.podsHandler, podStatusUpdate{updatedPod: pod})
+		ctx.Tell(i.podsHandler, podStatusUpdate{
+			updatedPod: pod, deleted: event.Type == watch.Deleted,
+		})
 	}
 
 	ctx.Log().Warn("pod informer stopped unexpectedly")
@@ -15,7 +15,6 @@ import (
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/aproto"
-	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 type (
@@ -23,8 +22,13 @@ type (
 )
 
 type podLogStreamer struct {
-	podHandler *actor.Ref
-	logReader  io.ReadCloser
+	// receiver is the actor the streamed log chunks are forwarded to as
+	// sproto.ContainerLog messages.
+	receiver  *actor.Ref
+	logReader io.ReadCloser
+	// follow is whether the underlying stream keeps waiting for new logs after the
+	// existing ones are exhausted. Without it, EOF means the stream is complete.
+	follow bool
 
 	ctx *actor.Context
 }
@@ -32,20 +36,19 @@ type podLogStreamer struct {
 func newPodLogStreamer(
 	podInterface typedV1.PodInterface,
 	podName string,
-	podHandler *actor.Ref,
+	receiver *actor.Ref,
+	logOptions *k8sV1.PodLogOptions,
 ) (*podLogStreamer, error) {
-	logs := podInterface.GetLogs(podName, &k8sV1.PodLogOptions{
-		Follow:     true,
-		Timestamps: false,
-		Container:  model.DeterminedK8ContainerName,
-	})
+	logs := podInterface.GetLogs(podName, logOptions)
 
 	logReader, err := logs.Stream(context.TODO())
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to initialize log stream for pod: %s", podName)
 	}
 
-	return &podLogStreamer{logReader: logReader, podHandler: podHandler}, nil
+	return &podLogStreamer{
+		logReader: logReader, receiver: receiver, follow: logOptions.Follow,
+	}, nil
 }
 
 func (p *podLogStreamer) Receive(ctx *actor.Context) error {
@@ -68,7 +71,7 @@ func (p *podLogStreamer) Receive(ctx *actor.Context) error {
 
 // Write implements the io.Writer interface.
 func (p *podLogStreamer) Write(log []byte) (n int, err error) {
-	p.ctx.Tell(p.podHandler, sproto.ContainerLog{
+	p.ctx.Tell(p.receiver, sproto.ContainerLog{
 		Timestamp: time.Now().UTC(),
 		RunMessage: &aproto.RunMessage{
 			Value:   string(log),
@@ -86,5 +89,11 @@ func (p *podLogStreamer) receiveStreamLogs(ctx *actor.Context) {
 		ctx.Self().Stop()
 		return
 	}
+	if !p.follow {
+		// A nil error means the stream hit EOF; without follow there is nothing left to
+		// read, so re-issuing the copy would spin forever.
+		ctx.Self().Stop()
+		return
+	}
 	ctx.Tell(ctx.Self(), streamLogs{})
 }
@@ -1,6 +1,11 @@
 package kubernetes
 
 import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/logger"
@@ -18,19 +23,87 @@ type (
 
 		LogContext logger.Context
 	}
-	// KillTaskPod notifies the pods actor to kill a pod.
+	// StartTaskPodGroup notifies the pods actor to start one pod per task spec, with the
+	// rank of each pod given by its index into Specs. When AllOrNothing is set, the group
+	// is first validated against the free slots of the current nodes and no pods are
+	// created unless every member of the group can be placed.
+	StartTaskPodGroup struct {
+		TaskActor    *actor.Ref
+		Specs        []tasks.TaskSpec
+		Slots        int
+		AllOrNothing bool
+
+		LogContext logger.Context
+	}
+
+	// KillTaskPod notifies the pods actor to kill a pod. GracePeriodSeconds bounds how
+	// long the pod has to exit after its SIGTERM before it is SIGKILLed; when nil, the
+	// resource manager's configured default applies. A second KillTaskPod for the same
+	// pod escalates to a forced deletion with no grace period.
 	KillTaskPod struct {
+		PodID              cproto.ID
+		GracePeriodSeconds *int64
+	}
+
+	// KillAllPodsForTask notifies the pods actor to kill every pod belonging to the
+	// given task actor in a single pass, so distributed tasks are torn down without a
+	// per-pod race against reconciliation. The task is marked as terminating until its
+	// last pod is cleaned up, and StartTaskPod requests from it are rejected in the
+	// meantime. A zero GracePeriod applies the resource manager's configured default.
+	// The pods actor replies with the container IDs of the pods it killed.
+	KillAllPodsForTask struct {
+		TaskActor   *actor.Ref
+		GracePeriod time.Duration
+	}
+
+	// SuspendTaskPod notifies the pods actor to suspend a pod, temporarily freeing its
+	// slots. Kubernetes cannot truly pause a pod, so suspension checkpoints the state
+	// needed to recreate the pod (spec, slots, rank, log context) along with its queue
+	// position, and only then deletes it. The pods actor replies with a PodSuspended
+	// confirmation once the checkpoint is recorded, before the pod is removed.
+	SuspendTaskPod struct {
 		PodID cproto.ID
 	}
 
-	// PreemptTaskPod notifies the pods actor to preempt a pod.
+	// ResumeTaskPod notifies the pods actor to recreate a previously suspended pod from
+	// its checkpointed state, restoring its original queue position. If the suspended
+	// pod is still terminating, the recreation is deferred until it finishes. The pods
+	// actor replies with an error if no suspended pod exists for the container.
+	ResumeTaskPod struct {
+		PodID cproto.ID
+	}
+
+	// RescheduleTaskPod notifies the pods actor to move a still-pending pod to a different
+	// node pool by deleting it and recreating it with scheduling constraints targeting the
+	// pool. Pods that have already started running cannot be rescheduled.
+	RescheduleTaskPod struct {
+		PodID      cproto.ID
+		TargetPool string
+	}
+
+	// PreemptTaskPod notifies the pods actor to preempt a pod. If GracePeriodSeconds is
+	// positive and the pod has not exited once the grace period elapses, the pods actor
+	// escalates to killing the pod. A repeated preemption resets the running grace period.
 	PreemptTaskPod struct {
-		PodName string
+		PodName            string
+		GracePeriodSeconds int64
 	}
 
-	// ChangePriority notifies the pods actor of a priority change and to preempt the specified pod.
+	// ChangePriority notifies the pods actor of a priority change for the specified pod.
+	// When Priority is set, the pod is recreated under the shared kubernetes priority
+	// class for that priority, so the kube-scheduler itself ranks it against
+	// non-Determined workloads. When Preempt is set, the pod is interrupted so the
+	// change applies immediately; otherwise the new priority is only recorded and takes
+	// effect the next time the pod is scheduled, leaving running work undisturbed.
+	//
+	// Pods placed by an external gang scheduler (the coscheduler or a scheduler named in
+	// the task spec) are never interrupted, even with Preempt set: the priority is
+	// recorded and preemption is deferred to that scheduler, which owns gang-level
+	// preemption decisions.
 	ChangePriority struct {
-		PodID cproto.ID
+		PodID    cproto.ID
+		Priority *int32
+		Preempt  bool
 	}
 
 	// SetPodOrder notifies the pods actor to set the queue position of a pod.
@@ -38,4 +111,369 @@ type (
 		QPosition float64
 		PodID     cproto.ID
 	}
+
+	// SetPodGroupOrder notifies the pods actor to set the same queue position for every
+	// pod belonging to the given task actor within a single message handler, so all
+	// ranks of a gang-scheduled job move through the queue together instead of being
+	// reordered one pod at a time. The pods actor replies with the number of pods it
+	// repositioned.
+	SetPodGroupOrder struct {
+		TaskActor *actor.Ref
+		QPosition float64
+	}
+
+	// DrainNode notifies the pods actor to cordon the given node and gracefully preempt
+	// every Determined pod running on it, escalating to a kill once the grace period
+	// elapses. The pods actor replies with the container IDs of the evicted pods.
+	// Draining an already-cordoned node re-issues preemption to any remaining pods.
+	DrainNode struct {
+		NodeName    string
+		GracePeriod time.Duration
+	}
+
+	// GetPodLogs requests that the logs of a pod be fetched from the kubernetes log API
+	// and forwarded to LogReceiver as sproto.ContainerLog messages. An empty Container
+	// defaults to the primary task container. The pods actor replies with the actor
+	// reference of the log streamer, which the caller may stop to cancel the stream, or
+	// with a ContainerNotFoundError if the requested container does not exist in the pod.
+	GetPodLogs struct {
+		PodID       cproto.ID
+		LogReceiver *actor.Ref
+		Container   string
+		SinceTime   *time.Time
+		Follow      bool
+	}
+
+	// GetPodPlacement requests the node name and IPs of the pod backing a container. The
+	// pods actor replies with a PodPlacement served from the pod state maintained by the
+	// informer watch, so the read costs no API server round trip, or with a
+	// PodNotScheduledError if the pod has not been placed on a node yet.
+	GetPodPlacement struct {
+		PodID cproto.ID
+	}
+
+	// GetPodEvents requests the kubernetes events associated with a pod, e.g.,
+	// FailedScheduling or FailedMount, so users can see why a pod is stuck without
+	// kubectl access. Since, when set, drops events older than it. The pods actor
+	// fetches the events from the events API filtered by the pod's involved object and
+	// replies with a []PodEvent ordered by timestamp.
+	GetPodEvents struct {
+		PodID cproto.ID
+		Since *time.Time
+	}
+
+	// GetPodStats requests the current resource usage of a pod. The pods actor replies
+	// with PodStats, or with a PodStatsNotReadyError if the pod is not registered or the
+	// metrics API has no sample for it yet.
+	GetPodStats struct {
+		PodID cproto.ID
+	}
+
+	// GetPodStatuses requests a compact status snapshot of many tracked pods in a single
+	// round trip, e.g., for dashboards that would otherwise issue a request per pod. An
+	// empty PodIDs means all tracked pods. The pods actor assembles the reply entirely
+	// from the pod state maintained by the informer watch, within one message handler,
+	// and replies with a map[cproto.ID]PodStatus; container IDs it does not track are
+	// simply absent from the map.
+	GetPodStatuses struct {
+		PodIDs []cproto.ID
+	}
+
+	// SubscribeToPodEvents registers Subscriber to receive a PodStateChanged message on
+	// every pod state transition the pods actor observes. Subscribers are automatically
+	// dropped when their actor stops.
+	SubscribeToPodEvents struct {
+		Subscriber *actor.Ref
+	}
+
+	// WaitForPodRunning requests a channel that resolves once the pod reaches the
+	// running state, so code outside the actor system can block on the transition
+	// instead of polling. The pods actor replies immediately with a <-chan error that
+	// yields nil once the pod is running, a PodTimedOutError if Timeout elapses first,
+	// or a PodFailedError if the pod terminates without ever running. The waiter is
+	// one-shot and deregistered on whichever of the three happens first, so it cannot
+	// leak.
+	WaitForPodRunning struct {
+		PodID   cproto.ID
+		Timeout time.Duration
+	}
+
+	// GetReconcileState requests a snapshot of how the pods tracked by the pods actor
+	// compare against the pods present in the cluster. The pods actor replies with a
+	// ReconcileState assembled within a single message handler, so the snapshot is a
+	// consistent read of its tracking state.
+	GetReconcileState struct{}
+
+	// ResyncPods requests an immediate full reconciliation against the cluster, rather
+	// than waiting for the next relist, e.g., after a network partition between the
+	// master and the API server. Namespace, when non-empty, limits the resync to that
+	// namespace. Unlike GetReconcileState, ResyncPods acts on what it finds: pods created
+	// by the job controller for tracked jobs are adopted, and determined-labeled pods
+	// that are not tracked are killed. The pods actor replies with a ReconcileState
+	// summarizing the outcome, using PodAdopted and PodUnexpected for the pods it acted
+	// on, so tooling built against GetReconcileState can consume it unchanged.
+	ResyncPods struct {
+		Namespace string
+	}
+
+	// SetPodResources requests an in-place resize of the CPU and memory requests of a
+	// running pod, without restarting it. The pods actor replies with PodResources
+	// reflecting the requests the API server actually applied, which may be clamped to
+	// LimitRange bounds, or with an error if the cluster does not support the in-place
+	// pod resize feature.
+	SetPodResources struct {
+		PodID  cproto.ID
+		CPU    resource.Quantity
+		Memory resource.Quantity
+	}
+
+	// GetResourceSummary requests cluster-wide slot counts derived from the node and pod
+	// state the pods actor already watches, so they stay consistent with scheduling
+	// decisions. The pods actor replies with a ResourceSummary.
+	GetResourceSummary struct{}
+
+	// GetQueuePosition requests the position of a pending pod among all pending
+	// determined pods, ordered the way the scheduler orders them: descending priority,
+	// then ascending queue position as set by SetPodOrder, then creation time. The pods
+	// actor replies with a QueuePosition.
+	GetQueuePosition struct {
+		PodID cproto.ID
+	}
+
+	// ValidateTaskSpec requests a server-side dry run of the pod a task spec would
+	// create, without creating any resources. TaskActor, when set, is recorded as the
+	// parent of the would-be container in the generated pod spec; the dry-run pod is
+	// never started, so the pods actor stands in when it is nil. The pods actor replies
+	// with a TaskSpecValidation, or with a plain error if the API server could not be
+	// reached, so callers can tell an invalid spec apart from a transient failure.
+	ValidateTaskSpec struct {
+		TaskActor *actor.Ref
+		Spec      tasks.TaskSpec
+		Slots     int
+	}
 )
+
+// PodReconcileStatus describes how a single pod compares against the cluster in a
+// ReconcileState snapshot.
+type PodReconcileStatus string
+
+const (
+	// PodPresent indicates a tracked pod that exists in the cluster.
+	PodPresent PodReconcileStatus = "Present"
+	// PodMissing indicates a tracked pod that no longer exists in the cluster, e.g.,
+	// because it was deleted out of band.
+	PodMissing PodReconcileStatus = "Missing"
+	// PodUnexpected indicates a determined-labeled pod that exists in the cluster but is
+	// not tracked by the pods actor. In a ResyncPods reply it also indicates the pod was
+	// killed.
+	PodUnexpected PodReconcileStatus = "Unexpected"
+	// PodAdopted indicates a pod the job controller created for a tracked job-mode task
+	// that the pods actor started tracking during a resync, e.g., because the watch event
+	// for it was missed.
+	PodAdopted PodReconcileStatus = "Adopted"
+)
+
+// ReconcileState is the snapshot of tracked pods versus cluster pods returned in
+// response to a GetReconcileState request.
+type ReconcileState struct {
+	Pods          map[string]PodReconcileStatus
+	LastReconcile time.Time
+}
+
+// ResourcePoolSummary aggregates slot counts for the nodes of one node pool and GPU
+// type. Pending demand is reported with an empty NodePool, since pending pods have not
+// been assigned a node yet. Shared slots, requested under an alternate device resource
+// name such as a time-sliced GPU, overcommit physical devices and are counted separately
+// so TotalSlots minus AllocatedSlots remains the free dedicated capacity.
+type ResourcePoolSummary struct {
+	NodePool             string
+	GPUType              string
+	TotalSlots           int
+	AllocatedSlots       int
+	PendingSlots         int
+	AllocatedSharedSlots int
+	PendingSharedSlots   int
+}
+
+// ResourceSummary is the reply to a GetResourceSummary request. Staleness is how long ago
+// the actor last reconciled its tracking state against the cluster; the node and pod
+// states the counts derive from are updated continuously by watches.
+type ResourceSummary struct {
+	Pools     []ResourcePoolSummary
+	Timestamp time.Time
+	Staleness time.Duration
+}
+
+// QueuePosition is the reply to a GetQueuePosition request. If Scheduled is true, the pod
+// has already been placed and the queue fields are zero. Otherwise Position is the pod's
+// 1-based rank among TotalPending pending pods and SlotsAhead estimates the slots demanded
+// by the pods ahead of it.
+type QueuePosition struct {
+	Scheduled    bool
+	Position     int
+	TotalPending int
+	SlotsAhead   int
+}
+
+// PodEvictedBySpot is sent to the task actor instead of a container failure when the pod
+// of a task that opted into preemptible nodes is evicted because its node was reclaimed.
+// Reason describes why the eviction was detected, e.g., the node turning NotReady.
+type PodEvictedBySpot struct {
+	ContainerID cproto.ID
+	Reason      string
+}
+
+// PodLostToNodeFailure is sent to the task actor instead of a container failure when a
+// pod is lost because its node failed (marked NodeLost, turned NotReady, or removed from
+// the cluster) rather than because the task itself failed. The pods actor detects the
+// loss from node transitions directly instead of waiting the several minutes kubernetes
+// takes to mark a lost node's pods as failed, and deletes the lost pod so it cannot
+// rejoin if the node recovers. The task layer can resubmit just this rank via
+// StartTaskPod instead of tearing down the whole gang.
+type PodLostToNodeFailure struct {
+	PodID  cproto.ID
+	Rank   int
+	Reason string
+}
+
+// PodExceededMaxRuntime is sent to the task actor when kubernetes terminates a pod for
+// exceeding the task's MaxRuntime wall-clock limit. The limit is enforced by the kubelet
+// through activeDeadlineSeconds, so it holds across master restarts, and the timeout is
+// reported distinctly from a crash of the task itself.
+type PodExceededMaxRuntime struct {
+	PodID      cproto.ID
+	MaxRuntime time.Duration
+}
+
+// PodSuspended is the reply to a SuspendTaskPod request. It is sent once the state
+// needed to resume the pod has been checkpointed and before the pod is deleted.
+type PodSuspended struct {
+	PodID cproto.ID
+}
+
+// PodStateChanged is sent to every actor registered through SubscribeToPodEvents when a
+// pod transitions between container states.
+type PodStateChanged struct {
+	PodID     cproto.ID
+	OldState  cproto.State
+	NewState  cproto.State
+	Reason    string
+	Timestamp time.Time
+}
+
+// TaskSpecValidation is the reply to a ValidateTaskSpec request. Errors holds the
+// rejections reported by the API server's admission chain, such as quota violations or
+// references to missing secrets.
+type TaskSpecValidation struct {
+	Valid  bool
+	Errors []string
+}
+
+// PodResources describes the resource requests applied to the task container of a pod.
+type PodResources struct {
+	CPU    resource.Quantity
+	Memory resource.Quantity
+}
+
+// PodEvent is one kubernetes event associated with a pod, in the reply to a
+// GetPodEvents request. The fields mirror the event's type, reason, message, and last
+// observed time, so callers can render or filter them without parsing log text.
+type PodEvent struct {
+	Type      string
+	Reason    string
+	Message   string
+	Count     int32
+	Timestamp time.Time
+}
+
+// PodStats describes a point-in-time sample of the resource usage of a single pod. GPU
+// utilization is deliberately absent: the pod metrics API only exposes allocated device
+// counts, not utilization, so reporting it needs a dedicated GPU metrics pipeline.
+type PodStats struct {
+	CPUMillicores int64
+	MemoryBytes   int64
+	Timestamp     time.Time
+}
+
+// PodStatus is the compact per-pod state returned by GetPodStatuses, sized for
+// dashboards rendering many pods at once.
+type PodStatus struct {
+	PodName      string
+	Phase        string
+	Reason       string
+	NodeName     string
+	Age          time.Duration
+	RestartCount int32
+}
+
+// PodPlacement reports where the pod backing a container landed: the node it was
+// scheduled onto and its pod and host IPs.
+type PodPlacement struct {
+	PodName  string
+	NodeName string
+	PodIP    string
+	HostIP   string
+}
+
+// PodNotScheduledError is returned in response to a GetPodPlacement request for a pod
+// that has not been placed on a node yet.
+type PodNotScheduledError struct {
+	PodID cproto.ID
+}
+
+func (e PodNotScheduledError) Error() string {
+	return fmt.Sprintf("pod for container %s has not been scheduled yet", e.PodID)
+}
+
+// ContainerNotFoundError is returned in response to a GetPodLogs request naming a
+// container that does not exist in the pod.
+type ContainerNotFoundError struct {
+	PodName   string
+	Container string
+}
+
+func (e ContainerNotFoundError) Error() string {
+	return fmt.Sprintf("pod %s has no container named %s", e.PodName, e.Container)
+}
+
+// PodTimedOutError resolves a WaitForPodRunning waiter whose timeout elapsed before the
+// pod reached the running state.
+type PodTimedOutError struct {
+	PodID   cproto.ID
+	Timeout time.Duration
+}
+
+func (e PodTimedOutError) Error() string {
+	return fmt.Sprintf("pod with container %s did not reach running within %s", e.PodID, e.Timeout)
+}
+
+// PodFailedError resolves a WaitForPodRunning waiter whose pod terminated without ever
+// reaching the running state.
+type PodFailedError struct {
+	PodID cproto.ID
+}
+
+func (e PodFailedError) Error() string {
+	return fmt.Sprintf("pod with container %s terminated before reaching running", e.PodID)
+}
+
+// PodStatsNotReadyError is returned in response to a GetPodStats request for a pod that
+// is not registered or for which the metrics API has no sample yet. It distinguishes
+// "unknown" from a pod that is legitimately idle.
+type PodStatsNotReadyError struct {
+	PodID cproto.ID
+}
+
+func (e PodStatsNotReadyError) Error() string {
+	return fmt.Sprintf("no resource usage metrics are available for pod with container %s", e.PodID)
+}
+
+// PodGroupPlacementError is returned in response to an all-or-nothing StartTaskPodGroup
+// for which one or more members of the group could not be placed.
+type PodGroupPlacementError struct {
+	UnplaceableRanks []int
+}
+
+func (e PodGroupPlacementError) Error() string {
+	return fmt.Sprintf("unable to place pod group ranks %v", e.UnplaceableRanks)
+}
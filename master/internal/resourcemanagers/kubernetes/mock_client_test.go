@@ -89,6 +89,7 @@ type mockPodInterface struct {
 	pods map[string]*k8sV1.Pod
 	// Simulates latency of the real k8 API server.
 	operationalDelay time.Duration
+	listCount        int
 	mux              sync.Mutex
 }
 
@@ -153,6 +154,7 @@ func (m *mockPodInterface) List(
 	m.mux.Lock()
 	defer m.mux.Unlock()
 
+	m.listCount++
 	podList := &k8sV1.PodList{}
 	for _, pod := range m.pods {
 		podList.Items = append(podList.Items, *pod)
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/actor/actors"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
@@ -17,20 +19,33 @@ import (
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/tasks"
 
+	batchV1 "k8s.io/api/batch/v1"
 	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sClient "k8s.io/client-go/kubernetes"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 const (
-	initContainerTarSrcPath   = "/run/determined/temp/tar/src"
-	initContainerTarDstPath   = "/run/determined/temp/tar/dst"
-	initContainerWorkDir      = "/run/determined/temp/"
-	determinedLabel           = "determined"
-	determinedPreemptionLabel = "determined-preemption"
-	determinedSystemLabel     = "determined-system"
+	initContainerTarSrcPath      = "/run/determined/temp/tar/src"
+	initContainerTarDstPath      = "/run/determined/temp/tar/dst"
+	initContainerWorkDir         = "/run/determined/temp/"
+	determinedLabel              = "determined"
+	determinedPreemptionLabel    = "determined-preemption"
+	determinedSystemLabel        = "determined-system"
+	determinedNodePoolLabel      = "determined-node-pool"
+	determinedQueuePositionLabel = "determined-queue-position"
 )
 
+// DefaultGPUTypeNodeLabel is the node label that GPU-type node affinities match when the
+// resource manager config does not override it.
+const DefaultGPUTypeNodeLabel = "accelerator"
+
+// DefaultPodDeletionGracePeriodSeconds is the grace period applied to pod deletions
+// triggered by KillTaskPod when neither the message nor the resource manager config
+// specifies one.
+const DefaultPodDeletionGracePeriodSeconds int64 = 15
+
 // pod manages the lifecycle of a Kubernetes pod that executes a
 // Determined task. The lifecycle of the pod is managed based on
 // the status of the specified set of containers.
@@ -47,6 +62,7 @@ type pod struct {
 	loggingTLSConfig         model.TLSClientConfig
 	loggingConfig            model.LoggingConfig
 	slots                    int
+	rank                     int
 	podInterface             typedV1.PodInterface
 	configMapInterface       typedV1.ConfigMapInterface
 	resourceRequestQueue     *actor.Ref
@@ -54,19 +70,92 @@ type pod struct {
 	scheduler                string
 	slotType                 device.Type
 	slotResourceRequests     PodSlotResourceRequests
+	gpuTypeNodeLabel         string
+	killGracePeriodSeconds   int64
+	initTimeout              time.Duration
+	pendingTimeout           time.Duration
 	fluentConfig             FluentConfig
+	// clusterNodeCount is the number of nodes known when the pod was created, used to
+	// decide whether a node-spread constraint can be required or only preferred.
+	clusterNodeCount int
 
 	pod           *k8sV1.Pod
 	podName       string
 	configMap     *k8sV1.ConfigMap
 	configMapName string
+	// jobName is set when the task runs in Job mode. The job keeps the unique name and
+	// the job controller names the pods it creates, so podName is updated when the pods
+	// actor adopts the job's pod.
+	jobName string
 	// TODO: Drop this manufactured container obj all together.
 	container        cproto.Container
 	ports            []int
 	resourcesDeleted bool
+	rescheduling     bool
+	killReceived     bool
+	// suspended is set when the pod is deleted to suspend the task; the deletion is
+	// expected and the checkpointed state recreates the pod on resume, so the exit must
+	// not be reported to the task actor as a container failure.
+	suspended     bool
+	evictedBySpot bool
+	// lostToNodeFailure is set when the pod was lost to a node failure; the task actor
+	// was notified with a PodLostToNodeFailure message, so the termination must not be
+	// reported as a container failure on top of it.
+	lostToNodeFailure bool
+	// priority, when set, overrides the task priority when constructing the pod spec. It
+	// is set by ChangePriority and applied the next time the pod is created.
+	priority         *int32
 	testLogStreamer  bool
+	logStreamerCount int
 	containerNames   map[string]bool
 
+	// initDeadline is when the pod is treated as stuck initializing and killed. It is
+	// armed while init containers are running and reset every time one completes.
+	initDeadline            time.Time
+	completedInitContainers int
+	lastEventMessage        string
+
+	// pendingDeadline is when the pod is treated as permanently unschedulable and
+	// killed. It is armed on submission and cleared once the pod is scheduled, so time
+	// spent pulling images or initializing does not count against it.
+	pendingDeadline time.Time
+	// lastSchedulingFailure is the message of the most recent FailedScheduling event,
+	// attached to the unschedulable failure so users see the scheduler's reason.
+	lastSchedulingFailure string
+	// unschedulableFailure records that the pod was killed for exceeding the pending
+	// timeout, so the termination is reported with the scheduling failure instead of a
+	// generic one.
+	unschedulableFailure string
+
+	// pvcZones are the zones the task's PersistentVolumeClaims are pinned to, resolved
+	// before the pod spec is built so a soft node affinity can steer the pod there.
+	pvcZones []string
+
+	// exceededMaxRuntime records the kubelet terminating the pod for exceeding the
+	// task's wall-clock limit, so the termination is reported as a timeout rather than
+	// a generic container failure.
+	exceededMaxRuntime string
+
+	// imagePullFailure records the most recent image pull backoff observed on the pod,
+	// so a subsequent termination is reported as a credentials/registry problem rather
+	// than a generic container failure.
+	imagePullFailure string
+
+	// configurationFailure records the kubelet refusing to start a container because a
+	// referenced secret or configMap is missing, so the failure is reported as a
+	// configuration error the user can fix instead of a generic container failure.
+	configurationFailure string
+
+	// checkpointFetchFailure records a non-zero exit of the checkpoint fetch init
+	// container, so the termination is reported as a checkpoint fetch failure (e.g., a
+	// missing checkpoint) rather than a training crash.
+	checkpointFetchFailure string
+
+	// outOfEphemeralStorage records a kubelet eviction for exceeding ephemeral storage,
+	// so the termination is reported as an out-of-ephemeral-storage failure the user can
+	// fix by raising the task's EphemeralStorage reservation.
+	outOfEphemeralStorage string
+
 	logCtx logger.Context
 }
 
@@ -85,13 +174,63 @@ var DefaultFluentConfig = FluentConfig{
 	Image: "fluent/fluent-bit:1.6",
 }
 
+// PodCreationRetryConfig stores k8s-configurable retry options for pod creation requests
+// that fail with a transient API error.
+type PodCreationRetryConfig struct {
+	MaxRetries        int `json:"max_retries"`
+	MaxBackoffSeconds int `json:"max_backoff_seconds"`
+}
+
+// DefaultPodCreationRetryConfig stores defaults for k8s-configurable pod creation retries.
+var DefaultPodCreationRetryConfig = PodCreationRetryConfig{
+	MaxRetries:        5,
+	MaxBackoffSeconds: 30,
+}
+
 type getPodNodeInfo struct{}
 
+// podRescheduled notifies the pods actor that a rescheduled pod was resubmitted
+// under a new name so that it can update its pod name mappings.
+type podRescheduled struct {
+	oldName string
+	newName string
+}
+
+// jobPodAdopted is sent by the pods actor when it starts tracking a pod the job
+// controller created for a job-mode task, so the pod actor knows the pod's actual name.
+type jobPodAdopted struct {
+	podName string
+}
+
+// podEvicted is sent by the pods actor when it detects that the node controller is
+// evicting the pod from a lost node, e.g., a reclaimed spot instance.
+type podEvicted struct {
+	reason string
+}
+
+// initTimeoutElapsed is sent by the pod actor to itself when the init timeout of the pod
+// elapses. The deadline identifies the timer so reset deadlines are ignored when they
+// fire.
+type initTimeoutElapsed struct {
+	deadline time.Time
+}
+
+// pendingTimeoutElapsed is sent by the pod actor to itself when the pending timeout of
+// the pod elapses. The deadline identifies the timer so it is ignored once the pod has
+// been scheduled.
+type pendingTimeoutElapsed struct {
+	deadline time.Time
+}
+
 type podNodeInfo struct {
-	nodeName  string
-	numSlots  int
-	slotType  device.Type
-	container *cproto.Container
+	nodeName string
+	numSlots int
+	// sharedSlots is set when the slots are requested under an alternate device resource
+	// name (e.g., a time-sliced GPU) and so overcommit physical devices.
+	sharedSlots bool
+	slotType    device.Type
+	gpuType     string
+	container   *cproto.Container
 }
 
 func newPod(
@@ -112,7 +251,12 @@ func newPod(
 	slotType device.Type,
 	slotResourceRequests PodSlotResourceRequests,
 	scheduler string,
+	gpuTypeNodeLabel string,
+	killGracePeriodSeconds int64,
+	initTimeout time.Duration,
+	maxPendingTimeout time.Duration,
 	fluentConfig FluentConfig,
+	clusterNodeCount int,
 ) *pod {
 	podContainer := cproto.Container{
 		Parent: msg.TaskActor.Address(),
@@ -121,6 +265,17 @@ func newPod(
 	}
 	uniqueName := configureUniqueName(msg.Spec, msg.Rank)
 
+	// A task-specified scheduler overrides the resource manager's configured default, so
+	// everything downstream, including preemption handling, sees the effective scheduler.
+	if msg.Spec.SchedulerName != "" {
+		scheduler = msg.Spec.SchedulerName
+	}
+
+	// A task-specified pending timeout overrides the resource manager's default.
+	if msg.Spec.MaxPendingDuration > 0 {
+		maxPendingTimeout = msg.Spec.MaxPendingDuration
+	}
+
 	// The lifecycle of the containers specified in this map will be monitored.
 	// As soon as one or more of them exits outs, the pod will be terminated.
 	containerNames := map[string]bool{model.DeterminedK8ContainerName: true}
@@ -138,6 +293,7 @@ func newPod(
 		loggingTLSConfig:         loggingTLSConfig,
 		loggingConfig:            loggingConfig,
 		slots:                    msg.Slots,
+		rank:                     msg.Rank,
 		podInterface:             podInterface,
 		configMapInterface:       configMapInterface,
 		resourceRequestQueue:     resourceRequestQueue,
@@ -149,7 +305,12 @@ func newPod(
 		scheduler:                scheduler,
 		slotType:                 slotType,
 		slotResourceRequests:     slotResourceRequests,
+		gpuTypeNodeLabel:         gpuTypeNodeLabel,
+		killGracePeriodSeconds:   killGracePeriodSeconds,
+		initTimeout:              initTimeout,
+		pendingTimeout:           maxPendingTimeout,
 		fluentConfig:             fluentConfig,
+		clusterNodeCount:         clusterNodeCount,
 		logCtx: logger.MergeContexts(msg.LogContext, logger.Context{
 			"pod": uniqueName,
 		}),
@@ -163,6 +324,11 @@ func (p *pod) Receive(ctx *actor.Context) error {
 		if err := p.createPodSpecAndSubmit(ctx); err != nil {
 			return err
 		}
+		if p.pendingTimeout > 0 {
+			deadline := time.Now().Add(p.pendingTimeout)
+			p.pendingDeadline = deadline
+			actors.NotifyAfter(ctx, p.pendingTimeout, pendingTimeoutElapsed{deadline: deadline})
+		}
 
 	case resourceCreationFailed:
 		p.receiveResourceCreationFailed(ctx, msg)
@@ -175,20 +341,39 @@ func (p *pod) Receive(ctx *actor.Context) error {
 	case podEventUpdate:
 		p.receivePodEventUpdate(ctx, msg)
 
+	case podEvicted:
+		p.receivePodEvicted(ctx, msg)
+
+	case initTimeoutElapsed:
+		p.receiveInitTimeout(ctx, msg)
+
+	case pendingTimeoutElapsed:
+		p.receivePendingTimeout(ctx, msg)
+
 	case PreemptTaskPod:
 		ctx.Log().Info("received preemption command")
 		p.taskActor.System().Tell(p.taskActor, sproto.ReleaseResources{})
 
 	case ChangePriority:
-		ctx.Log().Info("interrupting pod to change priorities")
-		p.taskActor.System().Tell(p.taskActor, sproto.ReleaseResources{})
+		p.receiveChangePriority(ctx, msg)
 
 	case sproto.ContainerLog:
 		p.receiveContainerLog(ctx, msg)
 
 	case KillTaskPod:
-		ctx.Log().Info("received request to stop pod")
-		p.deleteKubernetesResources(ctx)
+		p.receiveKillTaskPod(ctx, msg)
+
+	case SuspendTaskPod:
+		p.receiveSuspendTaskPod(ctx, msg)
+
+	case RescheduleTaskPod:
+		if err := p.receiveReschedulePod(ctx, msg); err != nil {
+			return err
+		}
+
+	case jobPodAdopted:
+		ctx.Log().Infof("job %s created pod %s", p.jobName, msg.podName)
+		p.podName = msg.podName
 
 	case resourceCreationCancelled:
 		p.receiveResourceCreationCancelled(ctx)
@@ -199,11 +384,17 @@ func (p *pod) Receive(ctx *actor.Context) error {
 	case getPodNodeInfo:
 		p.receiveGetPodNodeInfo(ctx)
 
+	case GetPodPlacement:
+		p.receiveGetPodPlacement(ctx)
+
+	case GetPodLogs:
+		p.receiveGetPodLogs(ctx, msg)
+
 	case actor.PostStop:
 		defer p.finalizeTaskState(ctx)
 
 		if !p.leaveKubernetesResources {
-			p.deleteKubernetesResources(ctx)
+			p.deleteKubernetesResources(ctx, nil)
 		}
 
 	case actor.ChildStopped:
@@ -220,18 +411,86 @@ func (p *pod) Receive(ctx *actor.Context) error {
 }
 
 func (p *pod) createPodSpecAndSubmit(ctx *actor.Context) error {
+	p.resolvePVCZones(ctx)
+
 	if err := p.createPodSpec(ctx, p.scheduler); err != nil {
 		return err
 	}
 
+	if p.taskSpec.CreateMode == tasks.CreateModeJob {
+		p.jobName = p.podName
+		ctx.Tell(p.resourceRequestQueue, createKubernetesResources{
+			handler:       ctx.Self(),
+			jobSpec:       p.jobSpec(),
+			configMapSpec: p.configMap,
+			logContext:    p.logCtx,
+		})
+		return nil
+	}
+
 	ctx.Tell(p.resourceRequestQueue, createKubernetesResources{
 		handler:       ctx.Self(),
 		podSpec:       p.pod,
 		configMapSpec: p.configMap,
+		logContext:    p.logCtx,
 	})
 	return nil
 }
 
+// resolvePVCZones reads the zones the task's PersistentVolumeClaims are pinned to, so
+// configurePVCZoneAffinity can prefer those zones when building the pod spec. Resolution
+// failures only cost the affinity hint, so they are logged rather than failing the pod.
+func (p *pod) resolvePVCZones(ctx *actor.Context) {
+	if !p.taskSpec.PreferPVCZone || len(p.taskSpec.Volumes) == 0 {
+		return
+	}
+
+	zonesByClaim, err := pvcRequiredZones(p.clientSet, p.namespace, p.taskSpec.Volumes)
+	if err != nil {
+		ctx.Log().WithError(err).Warn("unable to resolve PVC zone constraints")
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, zones := range zonesByClaim {
+		for _, zone := range zones {
+			if !seen[zone] {
+				seen[zone] = true
+				p.pvcZones = append(p.pvcZones, zone)
+			}
+		}
+	}
+}
+
+// jobSpec wraps the already-constructed pod spec in a kubernetes Job, so retries and
+// completion are tracked by the job controller instead of the pod actor resubmitting
+// the pod itself. The pod spec's restart policy of Never makes each retry a fresh pod.
+func (p *pod) jobSpec() *batchV1.Job {
+	spec := batchV1.JobSpec{
+		Template: k8sV1.PodTemplateSpec{
+			ObjectMeta: metaV1.ObjectMeta{Labels: p.pod.ObjectMeta.Labels},
+			Spec:       p.pod.Spec,
+		},
+	}
+	if p.taskSpec.JobBackoffLimit > 0 {
+		backoffLimit := p.taskSpec.JobBackoffLimit
+		spec.BackoffLimit = &backoffLimit
+	}
+	if p.taskSpec.JobActiveDeadlineSeconds > 0 {
+		activeDeadlineSeconds := p.taskSpec.JobActiveDeadlineSeconds
+		spec.ActiveDeadlineSeconds = &activeDeadlineSeconds
+	}
+
+	return &batchV1.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      p.jobName,
+			Namespace: p.namespace,
+			Labels:    map[string]string{determinedLabel: p.taskSpec.AllocationID},
+		},
+		Spec: spec,
+	}
+}
+
 func (p *pod) receiveResourceCreationFailed(ctx *actor.Context, msg resourceCreationFailed) {
 	ctx.Log().WithError(msg.err).Error("pod actor notified that resource creation failed")
 	p.insertLog(ctx, time.Now().UTC(), msg.err.Error())
@@ -243,12 +502,26 @@ func (p *pod) receiveResourceCreationFailed(ctx *actor.Context, msg resourceCrea
 
 func (p *pod) receivePodStatusUpdate(ctx *actor.Context, msg podStatusUpdate) error {
 	p.pod = msg.updatedPod
+	p.trackSchedulingProgress()
+	p.trackInitProgress(ctx)
+	p.trackImagePullFailure(ctx)
+	p.trackConfigurationFailure(ctx)
+	p.trackCheckpointFetchFailure(ctx)
+	p.trackEphemeralStorageEviction(ctx)
+	p.trackRuntimeDeadlineExceeded(ctx)
 
 	containerState, err := getPodState(ctx, p.pod, p.containerNames)
 	if err != nil {
 		return err
 	}
 
+	// With a readiness probe configured, the pod is not reported as running until the
+	// probe passes, so peers do not connect before the task's listeners are bound.
+	if containerState == cproto.Running && p.taskSpec.ReadinessProbe != nil &&
+		!p.taskContainerReady() {
+		containerState = cproto.Starting
+	}
+
 	if containerState == p.container.State {
 		return nil
 	}
@@ -262,20 +535,25 @@ func (p *pod) receivePodStatusUpdate(ctx *actor.Context, msg podStatusUpdate) er
 		// We insert it here because our  current implementation of the trial actor requires it.
 		ctx.Log().Infof(
 			"transitioning pod state from %s to %s", p.container.State, cproto.Pulling)
-		p.container = p.container.Transition(cproto.Pulling)
+		p.transition(ctx, cproto.Pulling, "pulling task images")
 		p.informTaskResourcesState(ctx)
 
 		ctx.Log().Infof("transitioning pod state from %s to %s", p.container.State, containerState)
-		p.container = p.container.Transition(cproto.Starting)
+		p.transition(ctx, cproto.Starting, "starting task containers")
 		p.informTaskResourcesState(ctx)
 
 	case cproto.Running:
 		ctx.Log().Infof("transitioning pod state from %s to %s", p.container.State, containerState)
-		p.container = p.container.Transition(cproto.Running)
+		p.transition(ctx, cproto.Running, "pod running")
 		// testLogStreamer is a testing flag only set in the pod_tests.
 		// This allows us to bypass the need for a log streamer or REST server.
 		if !p.testLogStreamer {
-			logStreamer, err := newPodLogStreamer(p.podInterface, p.podName, ctx.Self())
+			logStreamer, err := newPodLogStreamer(p.podInterface, p.podName, ctx.Self(),
+				&k8sV1.PodLogOptions{
+					Follow:     true,
+					Timestamps: false,
+					Container:  model.DeterminedK8ContainerName,
+				})
 			if err != nil {
 				return err
 			}
@@ -311,8 +589,11 @@ func (p *pod) receivePodStatusUpdate(ctx *actor.Context, msg podStatusUpdate) er
 		if err != nil {
 			// When a pod is deleted, it is possible that it will exit before the
 			// determined containers generates an exit code. To check if this is
-			// the case we check if a deletion timestamp has been set.
-			if p.pod.ObjectMeta.DeletionTimestamp != nil {
+			// the case we check if a deletion timestamp has been set. Kubelet
+			// evictions similarly terminate the pod without container exit codes.
+			if p.pod.ObjectMeta.DeletionTimestamp != nil || p.outOfEphemeralStorage != "" ||
+				p.checkpointFetchFailure != "" || p.unschedulableFailure != "" ||
+				p.exceededMaxRuntime != "" {
 				ctx.Log().Info("unable to get exit code for pod setting exit code to 137")
 				exitCode = 137
 				exitMessage = ""
@@ -322,17 +603,48 @@ func (p *pod) receivePodStatusUpdate(ctx *actor.Context, msg podStatusUpdate) er
 		}
 
 		ctx.Log().Infof("transitioning pod state from %s to %s", p.container.State, containerState)
-		p.container = p.container.Transition(cproto.Terminated)
+		reason := exitMessage
+		if reason == "" {
+			reason = "pod exited"
+		}
+		p.transition(ctx, cproto.Terminated, reason)
 
 		var resourcesStopped sproto.ResourcesStopped
-		switch exitCode {
-		case aproto.SuccessExitCode:
+		switch {
+		case exitCode == aproto.SuccessExitCode:
 			ctx.Log().Infof("pod exited successfully")
+		case p.evictedBySpot:
+			// The task actor was already notified with a PodEvictedBySpot message; do
+			// not report a container failure on top of it so it can resubmit the task.
+			ctx.Log().Infof("pod terminated by node eviction")
+		case p.lostToNodeFailure:
+			// The task actor was already notified with a PodLostToNodeFailure message
+			// and can resubmit this rank; the loss is not a container failure.
+			ctx.Log().Infof("pod terminated by node failure")
+		case p.suspended:
+			// The pod was deleted to suspend the task and will be recreated from its
+			// checkpointed state on resume; the exit is not a container failure.
+			ctx.Log().Infof("pod terminated for suspension")
 		default:
-			ctx.Log().Infof("pod failed with exit code: %d %s", exitCode, exitMessage)
+			failureMessage := exitMessage
+			switch {
+			case p.exceededMaxRuntime != "":
+				failureMessage = p.exceededMaxRuntime
+			case p.unschedulableFailure != "":
+				failureMessage = p.unschedulableFailure
+			case p.checkpointFetchFailure != "":
+				failureMessage = p.checkpointFetchFailure
+			case p.outOfEphemeralStorage != "":
+				failureMessage = p.outOfEphemeralStorage
+			case p.imagePullFailure != "":
+				failureMessage = p.imagePullFailure
+			case p.configurationFailure != "":
+				failureMessage = p.configurationFailure
+			}
+			ctx.Log().Infof("pod failed with exit code: %d %s", exitCode, failureMessage)
 			resourcesStopped.Failure = sproto.NewResourcesFailure(
 				sproto.ContainerFailed,
-				exitMessage,
+				failureMessage,
 				sproto.ExitCode(exitCode))
 		}
 		p.informTaskResourcesStopped(ctx, resourcesStopped)
@@ -345,22 +657,187 @@ func (p *pod) receivePodStatusUpdate(ctx *actor.Context, msg podStatusUpdate) er
 	return nil
 }
 
-func (p *pod) deleteKubernetesResources(ctx *actor.Context) {
+func (p *pod) receiveKillTaskPod(ctx *actor.Context, msg KillTaskPod) {
+	if !p.killReceived {
+		ctx.Log().Info("received request to stop pod")
+		p.killReceived = true
+		gracePeriod := p.killGracePeriodSeconds
+		if msg.GracePeriodSeconds != nil {
+			gracePeriod = *msg.GracePeriodSeconds
+		}
+		p.deleteKubernetesResources(ctx, &gracePeriod)
+		return
+	}
+
+	// A repeated kill for a pod that is already being deleted escalates to a forced
+	// deletion with no grace period, for pods that ignore or outlive their SIGTERM.
+	ctx.Log().Info("received repeated request to stop pod; forcing immediate deletion")
+	forceGracePeriod := int64(0)
+	p.resourcesDeleted = false
+	p.deleteKubernetesResources(ctx, &forceGracePeriod)
+}
+
+func (p *pod) receiveSuspendTaskPod(ctx *actor.Context, msg SuspendTaskPod) {
+	ctx.Log().Info("deleting pod for suspension")
+	p.suspended = true
+	gracePeriod := p.killGracePeriodSeconds
+	p.deleteKubernetesResources(ctx, &gracePeriod)
+}
+
+func (p *pod) deleteKubernetesResources(ctx *actor.Context, gracePeriodSeconds *int64) {
 	if p.resourcesDeleted {
 		return
 	}
 
 	ctx.Log().Infof("requesting to delete kubernetes resources")
+	if p.jobName != "" {
+		// Deleting the job with foreground propagation also deletes its pods.
+		ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
+			handler:            ctx.Self(),
+			jobName:            p.jobName,
+			configMapName:      p.configMapName,
+			namespace:          p.namespace,
+			gracePeriodSeconds: gracePeriodSeconds,
+		})
+		p.resourcesDeleted = true
+		return
+	}
+
 	ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
-		handler:       ctx.Self(),
-		podName:       p.podName,
-		configMapName: p.configMapName,
+		handler:            ctx.Self(),
+		podName:            p.podName,
+		configMapName:      p.configMapName,
+		namespace:          p.namespace,
+		gracePeriodSeconds: gracePeriodSeconds,
 	})
 
 	p.resourcesDeleted = true
 }
 
+func (p *pod) receiveReschedulePod(ctx *actor.Context, msg RescheduleTaskPod) error {
+	switch p.container.State {
+	case cproto.Running, cproto.Terminated:
+		// We cannot move a live container; the pods actor verifies the scheduling state
+		// before forwarding reschedule requests, however state transitions may race.
+		ctx.Log().Warnf(
+			"ignoring reschedule request for pod in state %s", p.container.State)
+		return nil
+	}
+
+	oldName := p.podName
+	uniqueName := configureUniqueName(p.taskSpec, p.rank)
+	p.podName = uniqueName
+	p.configMapName = uniqueName
+	p.logCtx = logger.MergeContexts(p.logCtx, logger.Context{"pod": uniqueName})
+	ctx.AddLabels(p.logCtx)
+
+	ctx.Log().Infof("rescheduling pod %s to node pool %s as %s", oldName, msg.TargetPool, uniqueName)
+
+	// If the original creation request is still queued, the deletion request cancels it
+	// and the pod actor is notified with a `resourceCreationCancelled` message, which
+	// must not stop the actor while a reschedule is in flight.
+	p.rescheduling = true
+	ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
+		handler:       ctx.Self(),
+		podName:       oldName,
+		configMapName: oldName,
+		namespace:     p.namespace,
+	})
+
+	p.ports = nil
+	if err := p.createPodSpec(ctx, p.scheduler); err != nil {
+		return err
+	}
+	p.configurePoolScheduling(p.pod, msg.TargetPool)
+
+	ctx.Tell(p.resourceRequestQueue, createKubernetesResources{
+		handler:       ctx.Self(),
+		podSpec:       p.pod,
+		configMapSpec: p.configMap,
+		logContext:    p.logCtx,
+	})
+
+	ctx.Tell(ctx.Self().Parent(), podRescheduled{oldName: oldName, newName: uniqueName})
+	return nil
+}
+
+// taskContainerReady reports whether the kubelet marked the task container as ready,
+// i.e., its readiness probe passed.
+func (p *pod) taskContainerReady() bool {
+	for _, containerStatus := range p.pod.Status.ContainerStatuses {
+		if containerStatus.Name == model.DeterminedK8ContainerName {
+			return containerStatus.Ready
+		}
+	}
+	return false
+}
+
+// usesGangScheduler reports whether the pod is placed by an external gang scheduler,
+// i.e., the coscheduler or a task-specified scheduler. Gang schedulers own preemption
+// decisions for the whole gang, so the pod actor must not interrupt members directly.
+func (p *pod) usesGangScheduler() bool {
+	return p.scheduler == coscheduler || p.taskSpec.SchedulerName != ""
+}
+
+func (p *pod) receiveChangePriority(ctx *actor.Context, msg ChangePriority) {
+	if msg.Priority == nil {
+		if !msg.Preempt {
+			ctx.Log().Debug("ignoring priority change with no priority and preemption disabled")
+			return
+		}
+		if p.usesGangScheduler() {
+			ctx.Log().Infof(
+				"deferring preemption to scheduler %s, which owns gang preemption decisions",
+				p.scheduler)
+			return
+		}
+		ctx.Log().Info("interrupting pod to change priorities")
+		p.taskActor.System().Tell(p.taskActor, sproto.ReleaseResources{})
+		return
+	}
+
+	// A pod's priority is immutable once it is created, so the new priority can only be
+	// applied when the pod is recreated. Create the priority class up front so the
+	// recreated pod is admitted at the new priority, and apply the change on restart
+	// rather than in place, which avoids the kube-scheduler preempting the pod out from
+	// under its own priority change when the priority is lowered.
+	if err := p.ensurePriorityClass(priorityClassName(*msg.Priority), *msg.Priority); err != nil {
+		ctx.Log().WithError(err).Errorf(
+			"failed to create priority class for priority %d", *msg.Priority)
+		return
+	}
+	p.priority = msg.Priority
+
+	if p.pod != nil && p.pod.Spec.Priority != nil && *msg.Priority < *p.pod.Spec.Priority {
+		ctx.Log().Infof(
+			"lowering pod priority from %d to %d", *p.pod.Spec.Priority, *msg.Priority)
+	}
+
+	if !msg.Preempt {
+		ctx.Log().Infof(
+			"recorded priority %d; it takes effect the next time the pod is scheduled",
+			*msg.Priority)
+		return
+	}
+
+	if p.usesGangScheduler() {
+		ctx.Log().Infof(
+			"recorded priority %d; deferring preemption to scheduler %s, which owns gang "+
+				"preemption decisions", *msg.Priority, p.scheduler)
+		return
+	}
+
+	ctx.Log().Info("interrupting pod to change priorities")
+	p.taskActor.System().Tell(p.taskActor, sproto.ReleaseResources{})
+}
+
 func (p *pod) receiveResourceCreationCancelled(ctx *actor.Context) {
+	if p.rescheduling && !p.resourcesDeleted {
+		ctx.Log().Infof("pending resource creation was canceled by a reschedule")
+		p.rescheduling = false
+		return
+	}
+
 	ctx.Log().Infof("pod actor notified that resource creation was canceled")
 	p.resourcesDeleted = true
 	ctx.Self().Stop()
@@ -374,12 +851,101 @@ func (p *pod) receiveResourceDeletionFailed(
 	ctx.Self().Stop()
 }
 
+func (p *pod) receiveGetPodLogs(ctx *actor.Context, msg GetPodLogs) {
+	container := msg.Container
+	if container == "" {
+		container = model.DeterminedK8ContainerName
+	}
+
+	if !p.hasContainer(container) {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(ContainerNotFoundError{PodName: p.podName, Container: container})
+		}
+		return
+	}
+
+	if msg.LogReceiver == nil {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf(
+				"GetPodLogs for pod %s specifies no log receiver", p.podName))
+		}
+		return
+	}
+
+	logOptions := &k8sV1.PodLogOptions{
+		Follow:    msg.Follow,
+		Container: container,
+	}
+	if msg.SinceTime != nil {
+		sinceTime := metaV1.NewTime(*msg.SinceTime)
+		logOptions.SinceTime = &sinceTime
+	}
+
+	logStreamer, err := newPodLogStreamer(p.podInterface, p.podName, msg.LogReceiver, logOptions)
+	if err != nil {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(err)
+		}
+		return
+	}
+
+	p.logStreamerCount++
+	ref, ok := ctx.ActorOf(
+		fmt.Sprintf("%s-logs-%d", p.podName, p.logStreamerCount), logStreamer)
+	if !ok {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("log streamer %s already exists", ref.Address()))
+		}
+		return
+	}
+
+	// The caller may stop the returned reference to cancel the stream early.
+	if ctx.ExpectingResponse() {
+		ctx.Respond(ref)
+	}
+}
+
+func (p *pod) hasContainer(container string) bool {
+	if p.pod == nil {
+		return false
+	}
+	for _, c := range p.pod.Spec.Containers {
+		if c.Name == container {
+			return true
+		}
+	}
+	for _, c := range p.pod.Spec.InitContainers {
+		if c.Name == container {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *pod) receiveGetPodNodeInfo(ctx *actor.Context) {
 	ctx.Respond(podNodeInfo{
-		nodeName:  p.pod.Spec.NodeName,
-		numSlots:  p.slots,
-		slotType:  p.slotType,
-		container: &p.container,
+		nodeName:    p.pod.Spec.NodeName,
+		numSlots:    p.slots,
+		sharedSlots: p.taskSpec.SlotResourceName != "",
+		slotType:    p.slotType,
+		gpuType:     p.taskSpec.GPUType,
+		container:   &p.container,
+	})
+}
+
+// receiveGetPodPlacement reports where the pod landed, from the pod state maintained by
+// the informer watch, so callers do not have to query the API server themselves.
+func (p *pod) receiveGetPodPlacement(ctx *actor.Context) {
+	if p.pod == nil || p.pod.Spec.NodeName == "" {
+		ctx.Respond(PodNotScheduledError{PodID: p.container.ID})
+		return
+	}
+
+	ctx.Respond(PodPlacement{
+		PodName:  p.podName,
+		NodeName: p.pod.Spec.NodeName,
+		PodIP:    p.pod.Status.PodIP,
+		HostIP:   p.pod.Status.HostIP,
 	})
 }
 
@@ -388,13 +954,27 @@ func (p *pod) finalizeTaskState(ctx *actor.Context) {
 	// and the task handler with new state.
 	if p.container.State != cproto.Terminated {
 		ctx.Log().Warnf("updating container state after pod actor exited unexpectedly")
-		p.container = p.container.Transition(cproto.Terminated)
+		p.transition(ctx, cproto.Terminated, "pod actor exited while pod was running")
 
 		p.informTaskResourcesStopped(ctx, sproto.ResourcesError(
 			sproto.TaskError, errors.New("pod actor exited while pod was running")))
 	}
 }
 
+// transition moves the container to the given state and notifies the pods actor of the
+// transition so it can fan the event out to registered subscribers.
+func (p *pod) transition(ctx *actor.Context, state cproto.State, reason string) {
+	oldState := p.container.State
+	p.container = p.container.Transition(state)
+	ctx.Tell(ctx.Self().Parent(), PodStateChanged{
+		PodID:     p.container.ID,
+		OldState:  oldState,
+		NewState:  state,
+		Reason:    reason,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
 func (p *pod) informTaskResourcesState(ctx *actor.Context) {
 	ctx.Tell(p.taskActor, sproto.ResourcesStateChanged{
 		ResourcesID:    sproto.FromContainerID(p.container.ID),
@@ -483,6 +1063,246 @@ func (p *pod) preparePodUpdateMessage(msgText string) string {
 	return msgText
 }
 
+// trackSchedulingProgress disarms the pending timeout once the pod is scheduled, so
+// only time spent unschedulable counts against it, not image pulls or initialization.
+func (p *pod) trackSchedulingProgress() {
+	if p.pendingDeadline.IsZero() {
+		return
+	}
+	for _, condition := range p.pod.Status.Conditions {
+		if condition.Type == k8sV1.PodScheduled && condition.Status == k8sV1.ConditionTrue {
+			p.pendingDeadline = time.Time{}
+			return
+		}
+	}
+}
+
+// trackInitProgress arms the init timeout while init containers are running. The deadline
+// is reset every time another init container completes, so only an init container that
+// makes no progress for the full timeout is treated as stuck.
+func (p *pod) trackInitProgress(ctx *actor.Context) {
+	if p.initTimeout <= 0 || p.pod == nil {
+		return
+	}
+
+	initializing := false
+	completed := 0
+	for _, status := range p.pod.Status.InitContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode == 0 {
+			completed++
+			continue
+		}
+		initializing = true
+	}
+	if !initializing {
+		p.initDeadline = time.Time{}
+		return
+	}
+
+	if p.initDeadline.IsZero() || completed != p.completedInitContainers {
+		p.completedInitContainers = completed
+		deadline := time.Now().Add(p.initTimeout)
+		p.initDeadline = deadline
+		actors.NotifyAfter(ctx, p.initTimeout, initTimeoutElapsed{deadline: deadline})
+	}
+}
+
+// trackImagePullFailure records image pull backoffs observed on the pod's containers, so
+// users are told about the credentials or registry problem instead of a generic failure.
+func (p *pod) trackImagePullFailure(ctx *actor.Context) {
+	statuses := make([]k8sV1.ContainerStatus, 0,
+		len(p.pod.Status.InitContainerStatuses)+len(p.pod.Status.ContainerStatuses))
+	statuses = append(statuses, p.pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, p.pod.Status.ContainerStatuses...)
+
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting == nil {
+			continue
+		}
+		if waiting.Reason != "ImagePullBackOff" && waiting.Reason != "ErrImagePull" {
+			continue
+		}
+
+		message := fmt.Sprintf("container %s failed to pull its image: %s: %s",
+			status.Name, waiting.Reason, waiting.Message)
+		if p.imagePullFailure != message {
+			p.imagePullFailure = message
+			ctx.Log().Error(message)
+			p.insertLog(ctx, time.Now().UTC(), message)
+		}
+	}
+}
+
+// trackConfigurationFailure records the kubelet failing to construct a container because
+// a secret or configMap referenced by the spec does not exist, so users are pointed at
+// the missing object instead of a generic failure.
+func (p *pod) trackConfigurationFailure(ctx *actor.Context) {
+	statuses := make([]k8sV1.ContainerStatus, 0,
+		len(p.pod.Status.InitContainerStatuses)+len(p.pod.Status.ContainerStatuses))
+	statuses = append(statuses, p.pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, p.pod.Status.ContainerStatuses...)
+
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting == nil || waiting.Reason != "CreateContainerConfigError" {
+			continue
+		}
+
+		message := fmt.Sprintf("container %s has a configuration error: %s",
+			status.Name, waiting.Message)
+		if p.configurationFailure != message {
+			p.configurationFailure = message
+			ctx.Log().Error(message)
+			p.insertLog(ctx, time.Now().UTC(), message)
+		}
+	}
+}
+
+// trackCheckpointFetchFailure records a non-zero exit of the checkpoint fetch init
+// container, so the pod's termination is attributed to the checkpoint fetch rather than
+// to the task container, which never started.
+func (p *pod) trackCheckpointFetchFailure(ctx *actor.Context) {
+	if p.checkpointFetchFailure != "" {
+		return
+	}
+
+	for _, status := range p.pod.Status.InitContainerStatuses {
+		if status.Name != checkpointFetchContainerName {
+			continue
+		}
+		terminated := status.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			return
+		}
+
+		p.checkpointFetchFailure = fmt.Sprintf(
+			"checkpoint fetch failed with exit code %d: %s",
+			terminated.ExitCode, terminated.Message)
+		ctx.Log().Error(p.checkpointFetchFailure)
+		p.insertLog(ctx, time.Now().UTC(), p.checkpointFetchFailure)
+		return
+	}
+}
+
+// trackEphemeralStorageEviction records a kubelet eviction of the pod for exceeding
+// ephemeral storage or node disk pressure, so the termination is reported as a distinct
+// out-of-ephemeral-storage failure instead of a generic one.
+func (p *pod) trackEphemeralStorageEviction(ctx *actor.Context) {
+	if p.outOfEphemeralStorage != "" || p.pod.Status.Reason != "Evicted" {
+		return
+	}
+	if !strings.Contains(p.pod.Status.Message, "ephemeral") &&
+		!strings.Contains(p.pod.Status.Message, "DiskPressure") {
+		return
+	}
+
+	p.outOfEphemeralStorage = fmt.Sprintf(
+		"out of ephemeral storage: %s", p.pod.Status.Message)
+	ctx.Log().Error(p.outOfEphemeralStorage)
+	p.insertLog(ctx, time.Now().UTC(), p.outOfEphemeralStorage)
+}
+
+// trackRuntimeDeadlineExceeded records kubernetes terminating the pod for exceeding the
+// task's activeDeadlineSeconds, and notifies the task actor with a PodExceededMaxRuntime
+// message so the timeout is distinguishable from a crash of the task itself.
+func (p *pod) trackRuntimeDeadlineExceeded(ctx *actor.Context) {
+	if p.exceededMaxRuntime != "" || p.pod.Status.Reason != "DeadlineExceeded" {
+		return
+	}
+
+	p.exceededMaxRuntime = fmt.Sprintf(
+		"pod exceeded its maximum runtime of %s", p.taskSpec.MaxRuntime)
+	ctx.Log().Error(p.exceededMaxRuntime)
+	p.insertLog(ctx, time.Now().UTC(), p.exceededMaxRuntime)
+	p.taskActor.System().Tell(p.taskActor, PodExceededMaxRuntime{
+		PodID:      p.container.ID,
+		MaxRuntime: p.taskSpec.MaxRuntime,
+	})
+}
+
+// receiveInitTimeout kills a pod whose init containers made no progress for the full init
+// timeout, so stuck image pulls or data downloads do not hold a queue slot indefinitely.
+// The deletion surfaces to the task actor through the usual termination path.
+func (p *pod) receiveInitTimeout(ctx *actor.Context, msg initTimeoutElapsed) {
+	if p.initDeadline.IsZero() || !p.initDeadline.Equal(msg.deadline) {
+		// Init finished or another init container completed in the meantime.
+		return
+	}
+	p.initDeadline = time.Time{}
+
+	stuckContainer := ""
+	for _, status := range p.pod.Status.InitContainerStatuses {
+		if status.State.Terminated == nil || status.State.Terminated.ExitCode != 0 {
+			stuckContainer = status.Name
+			break
+		}
+	}
+
+	ctx.Log().Errorf("init container %s made no progress in %s; last event: %s",
+		stuckContainer, p.initTimeout, p.lastEventMessage)
+	p.insertLog(ctx, time.Now().UTC(), fmt.Sprintf(
+		"Pod stuck initializing for more than %s (init container %s); killing pod. "+
+			"Last event: %s", p.initTimeout, stuckContainer, p.lastEventMessage))
+	p.deleteKubernetesResources(ctx, nil)
+}
+
+// receivePendingTimeout kills a pod that is still unschedulable when its pending timeout
+// elapses, attaching the scheduler's most recent FailedScheduling reason so users see why
+// the pod could not be placed.
+func (p *pod) receivePendingTimeout(ctx *actor.Context, msg pendingTimeoutElapsed) {
+	if p.pendingDeadline.IsZero() || !p.pendingDeadline.Equal(msg.deadline) {
+		// The pod was scheduled in the meantime.
+		return
+	}
+	p.pendingDeadline = time.Time{}
+
+	p.unschedulableFailure = fmt.Sprintf(
+		"pod was unschedulable for more than %s", p.pendingTimeout)
+	if p.lastSchedulingFailure != "" {
+		p.unschedulableFailure = fmt.Sprintf(
+			"%s: %s", p.unschedulableFailure, p.lastSchedulingFailure)
+	}
+
+	ctx.Log().Error(p.unschedulableFailure)
+	p.insertLog(ctx, time.Now().UTC(), fmt.Sprintf(
+		"Killing pod: %s", p.unschedulableFailure))
+	p.deleteKubernetesResources(ctx, nil)
+}
+
+// receivePodEvicted handles the pods actor detecting that this pod is being lost to a
+// node failure. For tasks that opted into preemptible nodes, the task actor is notified
+// with a PodEvictedBySpot message; otherwise it is notified with a PodLostToNodeFailure
+// message carrying the rank to resubmit. Either way the subsequent pod termination is
+// not reported as a container failure, so the task layer can resubmit instead of
+// erroring.
+func (p *pod) receivePodEvicted(ctx *actor.Context, msg podEvicted) {
+	if p.evictedBySpot || p.lostToNodeFailure {
+		return
+	}
+
+	if p.taskSpec.AllowPreemptibleNodes {
+		p.evictedBySpot = true
+		ctx.Log().WithField("reason", msg.reason).Info("pod evicted from preemptible node")
+		p.taskActor.System().Tell(p.taskActor, PodEvictedBySpot{
+			ContainerID: p.container.ID,
+			Reason:      msg.reason,
+		})
+		return
+	}
+
+	p.lostToNodeFailure = true
+	ctx.Log().WithField("reason", msg.reason).Info("pod lost to node failure")
+	p.taskActor.System().Tell(p.taskActor, PodLostToNodeFailure{
+		PodID:  p.container.ID,
+		Rank:   p.rank,
+		Reason: msg.reason,
+	})
+	// Delete the lost pod so it cannot rejoin the job if the node recovers after the
+	// task layer has already resubmitted this rank.
+	p.deleteKubernetesResources(ctx, nil)
+}
+
 func (p *pod) receivePodEventUpdate(ctx *actor.Context, msg podEventUpdate) {
 	// We only forward messages while pods are starting up.
 	switch p.container.State {
@@ -492,6 +1312,10 @@ func (p *pod) receivePodEventUpdate(ctx *actor.Context, msg podEventUpdate) {
 
 	msgText := p.preparePodUpdateMessage(msg.event.Message)
 	msg.event.Message = msgText
+	p.lastEventMessage = msgText
+	if msg.event.Reason == "FailedScheduling" {
+		p.lastSchedulingFailure = msg.event.Message
+	}
 
 	message := fmt.Sprintf("Pod %s: %s", msg.event.InvolvedObject.Name, msgText)
 	p.insertLog(ctx, msg.event.CreationTimestamp.Time, message)
@@ -82,7 +82,8 @@ func createPod(
 		model.TLSClientConfig{}, model.TLSClientConfig{},
 		model.LoggingConfig{DefaultLoggingConfig: &model.DefaultLoggingConfig{}},
 		podInterface, configMapInterface, resourceRequestQueue, leaveKubernetesResources,
-		slotType, slotResourceRequests, "default-scheduler", DefaultFluentConfig,
+		slotType, slotResourceRequests, "default-scheduler", DefaultGPUTypeNodeLabel,
+		DefaultPodDeletionGracePeriodSeconds, 0, 0, DefaultFluentConfig, 1,
 	)
 
 	return newPodHandler
@@ -697,16 +698,29 @@ func TestKillTaskPod(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unable to pop message from resources receiver queue")
 	}
-	assert.Equal(t, message, deleteKubernetesResources{
-		handler:       ref,
-		podName:       newPod.podName,
-		configMapName: newPod.configMapName,
-	},
-	)
+	deleteMsg, ok := message.(deleteKubernetesResources)
+	if !ok {
+		t.Errorf("expected deleteKubernetesResources but received %s", reflect.TypeOf(message))
+	}
+	assert.Equal(t, deleteMsg.handler, ref)
+	assert.Equal(t, deleteMsg.podName, newPod.podName)
+	assert.Equal(t, deleteMsg.configMapName, newPod.configMapName)
+	assert.Equal(t, *deleteMsg.gracePeriodSeconds, DefaultPodDeletionGracePeriodSeconds)
 
+	// A second kill request for the same pod escalates to a forced deletion.
 	system.Ask(ref, KillTaskPod{})
 	time.Sleep(time.Second)
-	assert.Equal(t, podMap["resource"].GetLength(), 0)
+	assert.Equal(t, podMap["resource"].GetLength(), 1)
+
+	message, err = podMap["resource"].Pop()
+	if err != nil {
+		t.Errorf("Unable to pop message from resources receiver queue")
+	}
+	deleteMsg, ok = message.(deleteKubernetesResources)
+	if !ok {
+		t.Errorf("expected deleteKubernetesResources but received %s", reflect.TypeOf(message))
+	}
+	assert.Equal(t, *deleteMsg.gracePeriodSeconds, int64(0))
 }
 
 func TestResourceCreationCancelled(t *testing.T) {
@@ -810,3 +824,153 @@ func TestGetPodNodeInfo(t *testing.T) {
 	assert.Equal(t, podInfo.nodeName, newPod.pod.Spec.NodeName)
 	assert.Equal(t, podInfo.numSlots, newPod.slots)
 }
+
+func TestExtraPodLabelsAndAnnotations(t *testing.T) {
+	setupEntrypoint(t)
+	defer cleanup(t)
+
+	system := actor.NewSystem("test-sys")
+	_, actorMap := createReceivers(system)
+
+	commandSpec := tasks.GenericCommandSpec{
+		Base: tasks.TaskSpec{
+			AllocationID:     "task",
+			ContainerID:      "container",
+			ClusterID:        "cluster",
+			AgentUserGroup:   createAgentUserGroup(),
+			Owner:            createUser(),
+			UserSessionToken: "bogus",
+			ExtraPodLabels: map[string]string{
+				"cost-center":   "ml-research",
+				determinedLabel: "not-the-allocation-id",
+			},
+			ExtraPodAnnotations: map[string]string{"compliance": "high"},
+		},
+		Config: model.CommandConfig{Description: "test-config"},
+	}
+
+	newPod := createPod(
+		actorMap["task"],
+		actorMap["cluster"],
+		actorMap["resource"],
+		commandSpec.ToTaskSpec(nil),
+	)
+	_, _ = system.ActorOf(actor.Addr("pod-actor-test-metadata"), newPod)
+	time.Sleep(time.Millisecond * 500)
+
+	assert.Equal(t, newPod.pod.ObjectMeta.Labels["cost-center"], "ml-research")
+	// The reserved label must keep the value determined assigned to it.
+	assert.Equal(t, newPod.pod.ObjectMeta.Labels[determinedLabel], "task")
+	assert.Equal(t, newPod.pod.ObjectMeta.Annotations["compliance"], "high")
+}
+
+func TestSidecarAndInitContainers(t *testing.T) {
+	setupEntrypoint(t)
+	defer cleanup(t)
+
+	system := actor.NewSystem("test-sys")
+	_, actorMap := createReceivers(system)
+
+	commandSpec := tasks.GenericCommandSpec{
+		Base: tasks.TaskSpec{
+			AllocationID:     "task",
+			ContainerID:      "container",
+			ClusterID:        "cluster",
+			AgentUserGroup:   createAgentUserGroup(),
+			Owner:            createUser(),
+			UserSessionToken: "bogus",
+			Sidecars: []tasks.ContainerSpec{
+				{Name: "log-shipper", Image: "fluentd:latest", Env: map[string]string{"TOPIC": "logs"}},
+			},
+			InitContainers: []tasks.ContainerSpec{
+				{Name: "model-fetcher", Image: "busybox", Command: []string{"fetch-model"}},
+			},
+		},
+		Config: model.CommandConfig{Description: "test-config"},
+	}
+
+	newPod := createPod(
+		actorMap["task"],
+		actorMap["cluster"],
+		actorMap["resource"],
+		commandSpec.ToTaskSpec(nil),
+	)
+	_, _ = system.ActorOf(actor.Addr("pod-actor-test-containers"), newPod)
+	time.Sleep(time.Millisecond * 500)
+
+	containerNames := make(map[string]bool)
+	for _, container := range newPod.pod.Spec.Containers {
+		containerNames[container.Name] = true
+	}
+	assert.Assert(t, containerNames["log-shipper"])
+	assert.Assert(t, containerNames[model.DeterminedK8ContainerName])
+
+	initContainerNames := make(map[string]bool)
+	for _, container := range newPod.pod.Spec.InitContainers {
+		initContainerNames[container.Name] = true
+	}
+	assert.Assert(t, initContainerNames["model-fetcher"])
+
+	// Only the task container's exit should drive the pod's terminal state.
+	assert.Assert(t, !newPod.containerNames["log-shipper"])
+	assert.Assert(t, newPod.containerNames[model.DeterminedK8ContainerName])
+}
+
+func TestPVCMounts(t *testing.T) {
+	setupEntrypoint(t)
+	defer cleanup(t)
+
+	system := actor.NewSystem("test-sys")
+	_, actorMap := createReceivers(system)
+
+	commandSpec := tasks.GenericCommandSpec{
+		Base: tasks.TaskSpec{
+			AllocationID:     "task",
+			ContainerID:      "container",
+			ClusterID:        "cluster",
+			AgentUserGroup:   createAgentUserGroup(),
+			Owner:            createUser(),
+			UserSessionToken: "bogus",
+			Volumes: []tasks.PVCMount{
+				{ClaimName: "shared-datasets", MountPath: "/datasets", ReadOnly: true},
+			},
+		},
+		Config: model.CommandConfig{Description: "test-config"},
+	}
+
+	newPod := createPod(
+		actorMap["task"],
+		actorMap["cluster"],
+		actorMap["resource"],
+		commandSpec.ToTaskSpec(nil),
+	)
+	_, _ = system.ActorOf(actor.Addr("pod-actor-test-pvc"), newPod)
+	time.Sleep(time.Millisecond * 500)
+
+	var pvcVolume *k8sV1.Volume
+	for i, volume := range newPod.pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			pvcVolume = &newPod.pod.Spec.Volumes[i]
+		}
+	}
+	assert.Assert(t, pvcVolume != nil)
+	assert.Equal(t, pvcVolume.PersistentVolumeClaim.ClaimName, "shared-datasets")
+
+	var taskContainer *k8sV1.Container
+	for i, container := range newPod.pod.Spec.Containers {
+		if container.Name == model.DeterminedK8ContainerName {
+			taskContainer = &newPod.pod.Spec.Containers[i]
+		}
+	}
+	assert.Assert(t, taskContainer != nil)
+
+	mounted := false
+	for _, volumeMount := range taskContainer.VolumeMounts {
+		if volumeMount.MountPath == "/datasets" {
+			mounted = true
+			// Read-only claims must be mounted read-only.
+			assert.Assert(t, volumeMount.ReadOnly)
+		}
+	}
+	assert.Assert(t, mounted)
+}
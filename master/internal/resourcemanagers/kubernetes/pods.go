@@ -6,29 +6,40 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/determined-ai/determined/master/internal/job"
+	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/sproto"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/actor/actors"
 	"github.com/determined-ai/determined/master/pkg/actor/api"
 	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 
 	k8sV1 "k8s.io/api/core/v1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sClient "k8s.io/client-go/kubernetes"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	metricsClient "k8s.io/metrics/pkg/client/clientset/versioned"
+	metricsTypedV1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 
 	// Used to load all auth plugins.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -37,6 +48,66 @@ import (
 type podMetadata struct {
 	podName     string
 	containerID string
+	taskActor   *actor.Ref
+}
+
+// trackedPodStatus pairs the compact status served by GetPodStatuses with the pod's
+// creation time, from which the age is computed at read time.
+type trackedPodStatus struct {
+	status    PodStatus
+	createdAt time.Time
+}
+
+// suspendedPod is the checkpoint recorded when a pod is suspended: the original start
+// message recreates the pod on resume and qPosition restores its place in the queue.
+type suspendedPod struct {
+	start     StartTaskPod
+	qPosition float64
+	// resumeOnStop is set when a resume arrives while the suspended pod's handler is
+	// still tearing down; the pod is recreated once the handler stops, since recreating
+	// it earlier would collide with the live handler.
+	resumeOnStop bool
+}
+
+// podSchedulingTimes records when a pod passed each startup phase, so the scheduling
+// latency histograms can break slow starts out into queue wait, image pull, and
+// container start.
+type podSchedulingTimes struct {
+	submitted time.Time
+	scheduled time.Time
+	pulling   time.Time
+	running   bool
+}
+
+// podsDB is the subset of the database interface the pods actor uses to persist
+// pod-to-task mappings so they can be recovered after a master restart.
+type podsDB interface {
+	AddTaskPod(*model.TaskPod) error
+	DeleteTaskPod(podName string) error
+	TaskPods() ([]*model.TaskPod, error)
+}
+
+// podEventSubscriberStopped is sent to the pods actor when an actor registered through
+// SubscribeToPodEvents stops, so the subscription does not leak.
+type podEventSubscriberStopped struct {
+	subscriber *actor.Ref
+}
+
+// runningWaitTimeout is sent by the pods actor to itself when the timeout of a
+// WaitForPodRunning request elapses. The channel identifies the waiter, which a state
+// transition may have already resolved and deregistered in the meantime.
+type runningWaitTimeout struct {
+	podID   string
+	timeout time.Duration
+	ch      chan error
+}
+
+// preemptionTimeout is sent by the pods actor to itself when the grace period of a
+// preempted pod elapses. The deadline identifies the preemption that armed the timer so
+// that reset or canceled grace periods are ignored.
+type preemptionTimeout struct {
+	podName  string
+	deadline time.Time
 }
 
 type patchStringValue struct {
@@ -45,24 +116,37 @@ type patchStringValue struct {
 	Value string `json:"value"`
 }
 
+type patchBoolValue struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value bool   `json:"value"`
+}
+
 // High lever overview of the actors within the kubernetes package:
 //
-//   pods
-//     +- pod(s): manages pod lifecycle. One per container in a task.
-//        +- podLogStreamer: stream logs for a specific pod.
-//     +- informer: sends updates about pod states
-//     +- events: sends updates about kubernetes events.
-//     +- requestQueue: queues requests to create / delete kubernetes resources.
-//        +- requestProcessingWorkers: processes request to create / delete kubernetes resources.
+//	pods
+//	  +- pod(s): manages pod lifecycle. One per container in a task.
+//	     +- podLogStreamer: stream logs for a specific pod.
+//	  +- informer: sends updates about pod states
+//	  +- events: sends updates about kubernetes events.
+//	  +- requestQueue: queues requests to create / delete kubernetes resources.
+//	     +- requestProcessingWorkers: processes request to create / delete kubernetes resources.
 type pods struct {
 	cluster                  *actor.Ref
+	db                       podsDB
 	namespace                string
+	allowNamespaceCreation   bool
 	masterServiceName        string
 	leaveKubernetesResources bool
 	scheduler                string
 	slotType                 device.Type
 	slotResourceRequests     PodSlotResourceRequests
+	gpuTypeNodeLabel         string
+	killGracePeriodSeconds   int64
+	initTimeout              time.Duration
+	maxPendingTimeout        time.Duration
 	fluentConfig             FluentConfig
+	creationRetry            PodCreationRetryConfig
 
 	clientSet        *k8sClient.Clientset
 	masterIP         string
@@ -83,10 +167,80 @@ type pods struct {
 	podHandlerToMetadata         map[*actor.Ref]podMetadata
 	nodeToSystemResourceRequests map[string]int64
 
+	// podNameToTaskPod holds the persisted pod-to-task record of every tracked pod.
+	// Entries restored from the database after a master restart have no pod handler.
+	podNameToTaskPod map[string]*model.TaskPod
+
+	// terminatingTasks are the task actors torn down through KillAllPodsForTask that
+	// still have pods being cleaned up. StartTaskPod requests from them are rejected so
+	// no new pod spins up mid-teardown.
+	terminatingTasks map[*actor.Ref]bool
+
+	// terminatingPods are the tracked pods known to be in the process of deletion,
+	// either because a kill was issued through this actor or because a status update
+	// carried a deletion timestamp. Queue and priority mutations against them are
+	// dropped, since patching a pod mid-deletion only produces 404s.
+	terminatingPods map[string]bool
+
+	// taskNamespaces are the namespaces the actor creates task pods in: the configured
+	// default namespace plus every workspace namespace a task has targeted so far.
+	taskNamespaces map[string]bool
+
+	// podStateSubscribers are the actors registered through SubscribeToPodEvents that
+	// receive a PodStateChanged message on every observed pod state transition.
+	podStateSubscribers map[*actor.Ref]bool
+
+	// runningWaiters are the one-shot WaitForPodRunning channels, keyed by container ID.
+	// Each is resolved and deregistered by the pod running, the pod terminating, or its
+	// timeout elapsing, whichever happens first.
+	runningWaiters map[string][]chan error
+
 	currentNodes map[string]*k8sV1.Node
 
-	podInterface       typedV1.PodInterface
-	configMapInterface typedV1.ConfigMapInterface
+	podInterface        typedV1.PodInterface
+	configMapInterface  typedV1.ConfigMapInterface
+	podMetricsInterface metricsTypedV1beta1.PodMetricsInterface
+
+	podStatsCache map[string]PodStats
+
+	// podStatuses holds a compact status per pod, updated from the informer watch, so
+	// bulk GetPodStatuses reads are served without fanning out to the pod actors or
+	// touching the API server.
+	podStatuses map[string]trackedPodStatus
+
+	// preemptionDeadlines tracks, per preempted pod, when the preemption grace period
+	// elapses and the pod is force killed.
+	preemptionDeadlines map[string]time.Time
+
+	// lastReconcile is when the actor last compared its tracking state against the pods
+	// present in the cluster.
+	lastReconcile time.Time
+
+	// podSchedulingTimes records, per pod, when each startup phase was reached, for the
+	// scheduling latency histograms.
+	podSchedulingTimes map[string]*podSchedulingTimes
+
+	// startMessages holds, per pod, the StartTaskPod message that created it, so a
+	// suspended pod can be recreated from the same state on resume.
+	startMessages map[string]StartTaskPod
+
+	// containerIDToQPosition tracks the queue position last set for each container
+	// through SetPodOrder, so it can be checkpointed on suspension.
+	containerIDToQPosition map[string]float64
+
+	// suspendedPods are the checkpoints of suspended pods, keyed by container ID. They
+	// outlive the pod handler, which stops once the suspended pod is deleted.
+	suspendedPods map[string]suspendedPod
+
+	// clusterPods caches the determined pods present in the cluster, keyed by pod name
+	// to namespace. It is seeded from the startup LIST and maintained from watch events
+	// afterwards, so steady-state reconciliation does not issue LIST calls against the
+	// API server.
+	clusterPods map[string]string
+
+	// relistRateLimiter caps how often reconciliation may fall back to a full LIST of
+	// cluster pods to heal the cache from missed watch events.
+	relistRateLimiter flowcontrol.RateLimiter
 }
 
 // PodsInfo contains information for pods.
@@ -103,7 +257,9 @@ func Initialize(
 	s *actor.System,
 	e *echo.Echo,
 	c *actor.Ref,
+	db podsDB,
 	namespace string,
+	allowNamespaceCreation bool,
 	masterServiceName string,
 	masterTLSConfig model.TLSClientConfig,
 	loggingConfig model.LoggingConfig,
@@ -111,7 +267,12 @@ func Initialize(
 	scheduler string,
 	slotType device.Type,
 	slotResourceRequests PodSlotResourceRequests,
+	gpuTypeNodeLabel string,
+	killGracePeriodSeconds int64,
+	initTimeoutSeconds int64,
+	maxPendingSeconds int64,
 	fluentConfig FluentConfig,
+	creationRetry PodCreationRetryConfig,
 ) *actor.Ref {
 	loggingTLSConfig := masterTLSConfig
 	if loggingConfig.ElasticLoggingConfig != nil {
@@ -120,7 +281,9 @@ func Initialize(
 
 	podsActor, ok := s.ActorOf(actor.Addr("pods"), &pods{
 		cluster:                      c,
+		db:                           db,
 		namespace:                    namespace,
+		allowNamespaceCreation:       allowNamespaceCreation,
 		masterServiceName:            masterServiceName,
 		masterTLSConfig:              masterTLSConfig,
 		scheduler:                    scheduler,
@@ -134,9 +297,30 @@ func Initialize(
 		leaveKubernetesResources:     leaveKubernetesResources,
 		slotType:                     slotType,
 		slotResourceRequests:         slotResourceRequests,
+		gpuTypeNodeLabel:             gpuTypeNodeLabel,
+		killGracePeriodSeconds:       killGracePeriodSeconds,
+		initTimeout:                  time.Duration(initTimeoutSeconds) * time.Second,
+		maxPendingTimeout:            time.Duration(maxPendingSeconds) * time.Second,
 		fluentConfig:                 fluentConfig,
+		creationRetry:                creationRetry,
 		currentNodes:                 make(map[string]*k8sV1.Node),
 		nodeToSystemResourceRequests: make(map[string]int64),
+		podNameToTaskPod:             make(map[string]*model.TaskPod),
+		terminatingTasks:             make(map[*actor.Ref]bool),
+		terminatingPods:              make(map[string]bool),
+		podSchedulingTimes:           make(map[string]*podSchedulingTimes),
+		startMessages:                make(map[string]StartTaskPod),
+		containerIDToQPosition:       make(map[string]float64),
+		suspendedPods:                make(map[string]suspendedPod),
+		taskNamespaces:               map[string]bool{namespace: true},
+		podStateSubscribers:          make(map[*actor.Ref]bool),
+		runningWaiters:               make(map[string][]chan error),
+		podStatsCache:                make(map[string]PodStats),
+		podStatuses:                  make(map[string]trackedPodStatus),
+		preemptionDeadlines:          make(map[string]time.Time),
+		clusterPods:                  make(map[string]string),
+		relistRateLimiter: flowcontrol.NewTokenBucketRateLimiter(
+			float32(1/reconcileRelistPeriod.Seconds()), 1),
 	})
 	check.Panic(check.True(ok, "pods address already taken"))
 	s.Ask(podsActor, actor.Ping{}).Get()
@@ -159,6 +343,9 @@ func (p *pods) Receive(ctx *actor.Context) error {
 			return err
 		}
 		p.startResourceRequestQueue(ctx)
+		if err := p.restoreTaskPods(ctx); err != nil {
+			return err
+		}
 		if err := p.deleteExistingKubernetesResources(ctx); err != nil {
 			return err
 		}
@@ -174,9 +361,17 @@ func (p *pods) Receive(ctx *actor.Context) error {
 			return err
 		}
 
+	case StartTaskPodGroup:
+		if err := p.receiveStartTaskPodGroup(ctx, msg); err != nil {
+			return err
+		}
+
 	case SetPodOrder:
 		p.receiveJobQueueMsg(ctx)
 
+	case SetPodGroupOrder:
+		p.receiveSetPodGroupOrder(ctx, msg)
+
 	case podStatusUpdate:
 		p.receivePodStatusUpdate(ctx, msg)
 
@@ -189,15 +384,86 @@ func (p *pods) Receive(ctx *actor.Context) error {
 	case PreemptTaskPod:
 		p.receivePodPreemption(ctx, msg)
 
+	case preemptionTimeout:
+		p.receivePreemptionTimeout(ctx, msg)
+
+	case DrainNode:
+		p.receiveDrainNode(ctx, msg)
+
 	case ChangePriority:
 		p.receivePriorityChange(ctx, msg)
 
 	case KillTaskPod:
 		p.receiveKillPod(ctx, msg)
 
+	case KillAllPodsForTask:
+		p.receiveKillAllPodsForTask(ctx, msg)
+
+	case SuspendTaskPod:
+		p.receiveSuspendTaskPod(ctx, msg)
+
+	case ResumeTaskPod:
+		if err := p.receiveResumeTaskPod(ctx, msg); err != nil {
+			return err
+		}
+
+	case RescheduleTaskPod:
+		p.receivePodReschedule(ctx, msg)
+
+	case podRescheduled:
+		p.receivePodRescheduled(ctx, msg)
+
 	case SummarizeResources:
 		p.receiveResourceSummarize(ctx, msg)
 
+	case GetResourceSummary:
+		p.receiveGetResourceSummary(ctx, msg)
+
+	case GetPodStats:
+		p.receiveGetPodStats(ctx, msg)
+
+	case GetPodStatuses:
+		p.receiveGetPodStatuses(ctx, msg)
+
+	case GetPodEvents:
+		p.receiveGetPodEvents(ctx, msg)
+
+	case GetReconcileState:
+		p.receiveGetReconcileState(ctx, msg)
+
+	case ResyncPods:
+		p.receiveResyncPods(ctx, msg)
+
+	case GetQueuePosition:
+		p.receiveGetQueuePosition(ctx, msg)
+
+	case SetPodResources:
+		p.receiveSetPodResources(ctx, msg)
+
+	case ValidateTaskSpec:
+		p.receiveValidateTaskSpec(ctx, msg)
+
+	case SubscribeToPodEvents:
+		p.receiveSubscribeToPodEvents(ctx, msg)
+
+	case podEventSubscriberStopped:
+		delete(p.podStateSubscribers, msg.subscriber)
+
+	case PodStateChanged:
+		p.receivePodStateChanged(ctx, msg)
+
+	case WaitForPodRunning:
+		p.receiveWaitForPodRunning(ctx, msg)
+
+	case runningWaitTimeout:
+		p.receiveRunningWaitTimeout(ctx, msg)
+
+	case GetPodLogs:
+		p.receiveGetPodLogs(ctx, msg)
+
+	case GetPodPlacement:
+		p.receiveGetPodPlacement(ctx, msg)
+
 	case resourceDeletionFailed:
 		if msg.err != nil {
 			ctx.Log().WithError(msg.err).Error("error deleting leftover kubernetes resource")
@@ -253,6 +519,12 @@ func (p *pods) startClientSet(ctx *actor.Context) error {
 	p.podInterface = p.clientSet.CoreV1().Pods(p.namespace)
 	p.configMapInterface = p.clientSet.CoreV1().ConfigMaps(p.namespace)
 
+	metricsClientSet, err := metricsClient.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize kubernetes metrics clientSet")
+	}
+	p.podMetricsInterface = metricsClientSet.MetricsV1beta1().PodMetricses(p.namespace)
+
 	ctx.Log().Infof("kubernetes clientSet initialized")
 	return nil
 }
@@ -289,37 +561,88 @@ func (p *pods) getSystemResourceRequests(ctx *actor.Context) error {
 func (p *pods) deleteExistingKubernetesResources(ctx *actor.Context) error {
 	listOptions := metaV1.ListOptions{LabelSelector: determinedLabel}
 
-	configMaps, err := p.configMapInterface.List(context.TODO(), listOptions)
+	configMaps, err := p.clientSet.CoreV1().ConfigMaps(metaV1.NamespaceAll).List(
+		context.TODO(), listOptions)
 	if err != nil {
 		return errors.Wrap(err, "error listing existing config maps")
 	}
 	for _, configMap := range configMaps.Items {
-		if configMap.Namespace != p.namespace {
+		if !p.taskNamespaces[configMap.Namespace] {
+			continue
+		}
+		if _, restored := p.podNameToTaskPod[configMap.Name]; restored {
+			continue
+		}
+
+		ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
+			handler: ctx.Self(), configMapName: configMap.Name, namespace: configMap.Namespace})
+	}
+
+	jobs, err := p.clientSet.BatchV1().Jobs(metaV1.NamespaceAll).List(context.TODO(), listOptions)
+	if err != nil {
+		return errors.Wrap(err, "error listing existing jobs")
+	}
+	for _, batchJob := range jobs.Items {
+		if !p.taskNamespaces[batchJob.Namespace] {
+			continue
+		}
+
+		// The job controller names the pods it creates after the job; a restored pod
+		// with such a name means the job belongs to a task that survived the restart.
+		restored := false
+		for podName := range p.podNameToTaskPod {
+			if podName == batchJob.Name || strings.HasPrefix(podName, batchJob.Name+"-") {
+				restored = true
+				break
+			}
+		}
+		if restored {
 			continue
 		}
 
 		ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
-			handler: ctx.Self(), configMapName: configMap.Name})
+			handler: ctx.Self(), jobName: batchJob.Name, namespace: batchJob.Namespace})
 	}
 
-	pods, err := p.podInterface.List(context.TODO(), listOptions)
+	pods, err := p.clientSet.CoreV1().Pods(metaV1.NamespaceAll).List(context.TODO(), listOptions)
 	if err != nil {
 		return errors.Wrap(err, "error listing existing pod")
 	}
 	for _, pod := range pods.Items {
-		if pod.Namespace != p.namespace {
+		p.clusterPods[pod.Name] = pod.Namespace
+		if !p.taskNamespaces[pod.Namespace] {
+			continue
+		}
+		if _, restored := p.podNameToTaskPod[pod.Name]; restored {
+			continue
+		}
+
+		// Job-owned pods are cleaned up by the foreground deletion of their job above;
+		// deleting them directly would just have the job controller spawn replacements.
+		jobOwned := false
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind == "Job" {
+				jobOwned = true
+				break
+			}
+		}
+		if jobOwned {
 			continue
 		}
 
 		ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
-			handler: ctx.Self(), podName: pod.Name})
+			handler: ctx.Self(), podName: pod.Name, namespace: pod.Namespace})
 	}
 
+	p.lastReconcile = time.Now().UTC()
 	return nil
 }
 
 func (p *pods) startPodInformer(ctx *actor.Context) {
-	p.informer, _ = ctx.ActorOf("pod-informer", newInformer(p.podInterface, p.namespace, ctx.Self()))
+	// Watch across all namespaces so that status updates for pods in workspace
+	// namespaces reach their pod handlers.
+	p.informer, _ = ctx.ActorOf("pod-informer", newInformer(
+		p.clientSet.CoreV1().Pods(metaV1.NamespaceAll), metaV1.NamespaceAll, ctx.Self()))
 }
 
 func (p *pods) startNodeInformer(ctx *actor.Context) {
@@ -327,28 +650,68 @@ func (p *pods) startNodeInformer(ctx *actor.Context) {
 }
 
 func (p *pods) startEventListener(ctx *actor.Context) {
+	// Listen across all namespaces, like the pod informer, so events for pods in
+	// workspace namespaces reach their pod handlers.
 	p.eventListener, _ = ctx.ActorOf(
-		"event-listener", newEventListener(p.clientSet, p.namespace, ctx.Self()))
+		"event-listener", newEventListener(p.clientSet, metaV1.NamespaceAll, ctx.Self()))
 }
 
 func (p *pods) startPreemptionListener(ctx *actor.Context) {
+	// Listen across all namespaces so label-based preemption reaches pods outside the
+	// default namespace.
 	p.preemptionListener, _ = ctx.ActorOf(
-		"preemption-listener", newPreemptionListener(p.clientSet, p.namespace, ctx.Self()))
+		"preemption-listener", newPreemptionListener(p.clientSet, metaV1.NamespaceAll, ctx.Self()))
 }
 
 func (p *pods) startResourceRequestQueue(ctx *actor.Context) {
 	p.resourceRequestQueue, _ = ctx.ActorOf(
 		"kubernetes-resource-request-queue",
-		newRequestQueue(p.podInterface, p.configMapInterface),
+		newRequestQueue(
+			p.podInterface, p.configMapInterface,
+			p.clientSet.BatchV1().Jobs(p.namespace), p.creationRetry),
 	)
 }
 
 func (p *pods) receiveStartTaskPod(ctx *actor.Context, msg StartTaskPod) error {
+	if p.terminatingTasks[msg.TaskActor] {
+		ctx.Log().WithField("task-actor", msg.TaskActor.Address()).Info(
+			"rejecting pod start for task that is being torn down")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("task %s is being torn down", msg.TaskActor.Address()))
+		}
+		return nil
+	}
+
+	if err := msg.Spec.ValidateResources(); err != nil {
+		ctx.Log().WithError(err).Error("rejecting pod start with invalid resource overrides")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(err)
+		}
+		return nil
+	}
+
+	if err := msg.Spec.ValidateDNS(); err != nil {
+		ctx.Log().WithError(err).Error("rejecting pod start with malformed DNS configuration")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(err)
+		}
+		return nil
+	}
+
+	namespace := p.namespace
+	if msg.Spec.Namespace != "" {
+		namespace = msg.Spec.Namespace
+	}
+	p.ensureNamespace(ctx, namespace)
+
 	newPodHandler := newPod(
-		msg, p.cluster, msg.Spec.ClusterID, p.clientSet, p.namespace, p.masterIP, p.masterPort,
-		p.masterTLSConfig, p.loggingTLSConfig, p.loggingConfig, p.podInterface, p.configMapInterface,
+		msg, p.cluster, msg.Spec.ClusterID, p.clientSet, namespace, p.masterIP, p.masterPort,
+		p.masterTLSConfig, p.loggingTLSConfig, p.loggingConfig,
+		p.podInterfaceFor(namespace), p.configMapInterfaceFor(namespace),
 		p.resourceRequestQueue, p.leaveKubernetesResources,
-		p.slotType, p.slotResourceRequests, p.scheduler, p.fluentConfig,
+		p.slotType, p.slotResourceRequests, p.scheduler, p.gpuTypeNodeLabel,
+		p.killGracePeriodSeconds, p.initTimeout, p.maxPendingTimeout, p.fluentConfig,
+		len(p.currentNodes),
 	)
 	ref, ok := ctx.ActorOf(fmt.Sprintf("pod-%s", msg.Spec.ContainerID), newPodHandler)
 	if !ok {
@@ -364,62 +727,484 @@ func (p *pods) receiveStartTaskPod(ctx *actor.Context, msg StartTaskPod) error {
 	}
 
 	p.podNameToPodHandler[newPodHandler.podName] = ref
+	delete(p.terminatingPods, newPodHandler.podName)
 	p.containerIDToPodName[msg.Spec.ContainerID] = newPodHandler.podName
 	p.podNameToContainerID[newPodHandler.podName] = msg.Spec.ContainerID
 	p.containerIDToSchedulingState[msg.Spec.ContainerID] = job.SchedulingStateQueued
 	p.podHandlerToMetadata[ref] = podMetadata{
 		podName:     newPodHandler.podName,
 		containerID: msg.Spec.ContainerID,
+		taskActor:   msg.TaskActor,
+	}
+	p.podSchedulingTimes[newPodHandler.podName] = &podSchedulingTimes{
+		submitted: time.Now().UTC(),
+	}
+	p.startMessages[newPodHandler.podName] = msg
+	if len(msg.Spec.Volumes) > 0 {
+		p.warnOnUnsatisfiablePVCZones(ctx, msg, namespace, ref)
+	}
+	p.persistTaskPod(ctx, &model.TaskPod{
+		PodName:      newPodHandler.podName,
+		Namespace:    namespace,
+		ContainerID:  msg.Spec.ContainerID,
+		AllocationID: model.AllocationID(msg.Spec.AllocationID),
+		Rank:         msg.Rank,
+		Slots:        msg.Slots,
+		LogContext:   model.JSONObj(msg.LogContext),
+	})
+
+	return nil
+}
+
+// warnOnUnsatisfiablePVCZones surfaces a clear task log message when a mounted
+// PersistentVolumeClaim is pinned to a zone with no nodes in the cluster, since the pod
+// can never schedule there and the resulting FailedScheduling events do not mention the
+// volume. Submission proceeds anyway: an autoscaler may still add nodes in that zone.
+func (p *pods) warnOnUnsatisfiablePVCZones(
+	ctx *actor.Context, msg StartTaskPod, namespace string, podHandler *actor.Ref,
+) {
+	zonesByClaim, err := pvcRequiredZones(p.clientSet, namespace, msg.Spec.Volumes)
+	if err != nil {
+		ctx.Log().WithError(err).Warn("unable to resolve PVC zone constraints")
+		return
+	}
+
+	nodeZones := make(map[string]bool)
+	for _, node := range p.currentNodes {
+		for _, label := range pvcZoneLabels {
+			if zone, ok := node.ObjectMeta.Labels[label]; ok {
+				nodeZones[zone] = true
+			}
+		}
+	}
+
+	for claim, zones := range zonesByClaim {
+		satisfiable := false
+		for _, zone := range zones {
+			if nodeZones[zone] {
+				satisfiable = true
+				break
+			}
+		}
+		if satisfiable {
+			continue
+		}
+
+		warning := fmt.Sprintf(
+			"PVC %s requires zone %s, but no nodes in the cluster are in that zone",
+			claim, strings.Join(zones, " or "))
+		ctx.Log().Warn(warning)
+		ctx.Tell(podHandler, sproto.ContainerLog{
+			Timestamp:  time.Now().UTC(),
+			AuxMessage: &warning,
+		})
+	}
+}
+
+func (p *pods) podInterfaceFor(namespace string) typedV1.PodInterface {
+	if namespace == p.namespace {
+		return p.podInterface
+	}
+	return p.clientSet.CoreV1().Pods(namespace)
+}
+
+func (p *pods) configMapInterfaceFor(namespace string) typedV1.ConfigMapInterface {
+	if namespace == p.namespace {
+		return p.configMapInterface
+	}
+	return p.clientSet.CoreV1().ConfigMaps(namespace)
+}
+
+// ensureNamespace prepares a namespace the first time a task pod targets it. The namespace
+// is created if it does not exist and the resource manager is configured to allow that;
+// otherwise pod creation proceeds anyway and the missing namespace surfaces through the
+// usual resource creation failure path. The request queue workers are told about the
+// namespace so creation and deletion requests are issued against the right interfaces.
+func (p *pods) ensureNamespace(ctx *actor.Context, namespace string) {
+	if p.taskNamespaces[namespace] {
+		return
+	}
+	p.taskNamespaces[namespace] = true
+	ctx.Tell(p.resourceRequestQueue, registerNamespace{
+		namespace:          namespace,
+		podInterface:       p.podInterfaceFor(namespace),
+		configMapInterface: p.configMapInterfaceFor(namespace),
+		jobInterface:       p.clientSet.BatchV1().Jobs(namespace),
+	})
+
+	_, err := p.clientSet.CoreV1().Namespaces().Get(
+		context.TODO(), namespace, metaV1.GetOptions{})
+	switch {
+	case err == nil:
+	case k8error.IsNotFound(err):
+		if !p.allowNamespaceCreation {
+			ctx.Log().Errorf(
+				"namespace %s does not exist and namespace creation is disabled; "+
+					"pods targeting it will fail to start", namespace)
+			return
+		}
+		_, err = p.clientSet.CoreV1().Namespaces().Create(context.TODO(), &k8sV1.Namespace{
+			ObjectMeta: metaV1.ObjectMeta{Name: namespace},
+		}, metaV1.CreateOptions{})
+		if err != nil && !k8error.IsAlreadyExists(err) {
+			ctx.Log().WithError(err).Errorf("failed to create namespace %s", namespace)
+			return
+		}
+		ctx.Log().Infof("created namespace %s", namespace)
+	default:
+		ctx.Log().WithError(err).Warnf("failed to check that namespace %s exists", namespace)
+	}
+}
+
+// restoreTaskPods rehydrates the pod-to-task mappings that were persisted before the
+// master restarted. Restored pods have no pod handler; kill and preemption requests
+// for them fall back to deleting their kubernetes resources directly.
+func (p *pods) restoreTaskPods(ctx *actor.Context) error {
+	if p.db == nil {
+		return nil
+	}
+
+	taskPods, err := p.db.TaskPods()
+	if err != nil {
+		return errors.Wrap(err, "error restoring pod-to-task mappings")
+	}
+
+	for _, taskPod := range taskPods {
+		p.podNameToTaskPod[taskPod.PodName] = taskPod
+		p.podNameToContainerID[taskPod.PodName] = taskPod.ContainerID
+		p.containerIDToPodName[taskPod.ContainerID] = taskPod.PodName
+		p.containerIDToSchedulingState[taskPod.ContainerID] = job.SchedulingStateQueued
+		// Re-register the pod's namespace with the request queue workers, so deletion
+		// requests for the restored pod are issued against the right interfaces.
+		if taskPod.Namespace != "" && taskPod.Namespace != p.namespace {
+			p.ensureNamespace(ctx, taskPod.Namespace)
+		}
+	}
+	if len(taskPods) > 0 {
+		ctx.Log().Infof("restored %d pod-to-task mappings from the database", len(taskPods))
+	}
+	return nil
+}
+
+func (p *pods) persistTaskPod(ctx *actor.Context, taskPod *model.TaskPod) {
+	p.podNameToTaskPod[taskPod.PodName] = taskPod
+	if p.db == nil {
+		return
+	}
+	if err := p.db.AddTaskPod(taskPod); err != nil {
+		ctx.Log().WithField("pod-name", taskPod.PodName).WithError(err).Warn(
+			"failed to persist pod-to-task mapping")
+	}
+}
+
+// forgetTaskPod clears the persisted pod-to-task mapping once a pod reaches a terminal
+// state. For restored pods, which have no pod handler, it also clears the in-memory maps
+// that restoreTaskPods rehydrated.
+func (p *pods) forgetTaskPod(ctx *actor.Context, podName string) {
+	taskPod, ok := p.podNameToTaskPod[podName]
+	if !ok {
+		return
+	}
+	delete(p.podNameToTaskPod, podName)
+
+	if _, hasHandler := p.podNameToPodHandler[podName]; !hasHandler {
+		delete(p.podNameToContainerID, podName)
+		delete(p.containerIDToPodName, taskPod.ContainerID)
+		delete(p.containerIDToSchedulingState, taskPod.ContainerID)
+		delete(p.preemptionDeadlines, podName)
+	}
+
+	if p.db == nil {
+		return
+	}
+	if err := p.db.DeleteTaskPod(podName); err != nil {
+		ctx.Log().WithField("pod-name", podName).WithError(err).Warn(
+			"failed to delete pod-to-task mapping")
+	}
+}
+
+// deleteRestoredPodResources is the fallback for kill and preemption requests targeting a
+// restored pod: the task actor that owned the pod did not survive the master restart, so
+// the only remaining action is to delete the kubernetes resources directly.
+func (p *pods) deleteRestoredPodResources(ctx *actor.Context, podName string) {
+	// Mappings persisted before the namespace was recorded fall back to the resource
+	// manager's default namespace.
+	namespace := p.namespace
+	if taskPod, ok := p.podNameToTaskPod[podName]; ok && taskPod.Namespace != "" {
+		namespace = taskPod.Namespace
+	}
+
+	ctx.Log().WithField("pod-name", podName).Info(
+		"deleting resources of restored pod with no pod handler")
+	ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
+		handler: ctx.Self(), podName: podName, configMapName: podName, namespace: namespace})
+	p.forgetTaskPod(ctx, podName)
+}
+
+func (p *pods) receiveStartTaskPodGroup(ctx *actor.Context, msg StartTaskPodGroup) error {
+	if p.terminatingTasks[msg.TaskActor] {
+		ctx.Log().WithField("task-actor", msg.TaskActor.Address()).Info(
+			"rejecting pod group start for task that is being torn down")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("task %s is being torn down", msg.TaskActor.Address()))
+		}
+		return nil
+	}
+
+	// Every spec is validated before any pod is created, so a bad rank cannot leave the
+	// group partially created.
+	for rank, spec := range msg.Specs {
+		if err := spec.ValidateResources(); err != nil {
+			ctx.Log().WithError(err).Errorf(
+				"rejecting pod group: rank %d has invalid resource overrides", rank)
+			if ctx.ExpectingResponse() {
+				ctx.Respond(errors.Wrapf(err, "invalid resource overrides for rank %d", rank))
+			}
+			return nil
+		}
+		if err := spec.ValidateDNS(); err != nil {
+			ctx.Log().WithError(err).Errorf(
+				"rejecting pod group: rank %d has malformed DNS configuration", rank)
+			if ctx.ExpectingResponse() {
+				ctx.Respond(errors.Wrapf(err, "malformed DNS configuration for rank %d", rank))
+			}
+			return nil
+		}
+	}
+
+	if msg.AllOrNothing {
+		if err := p.validatePodGroupPlacement(ctx, len(msg.Specs), msg.Slots); err != nil {
+			ctx.Log().WithError(err).Info("unable to place all-or-nothing pod group")
+			if ctx.ExpectingResponse() {
+				ctx.Respond(err)
+			}
+			return nil
+		}
+	}
+
+	started := make([]cproto.ID, 0, len(msg.Specs))
+	for rank, spec := range msg.Specs {
+		if err := p.receiveStartTaskPod(ctx, StartTaskPod{
+			TaskActor:  msg.TaskActor,
+			Spec:       spec,
+			Slots:      msg.Slots,
+			Rank:       rank,
+			LogContext: msg.LogContext,
+		}); err != nil {
+			// A later rank failed to start; kill the earlier ranks so the group never
+			// holds a partial allocation, and surface the failure to the caller.
+			ctx.Log().WithError(err).Errorf(
+				"failed to start rank %d; rolling back %d already started pods",
+				rank, len(started))
+			for _, podID := range started {
+				p.receiveKillPod(ctx, KillTaskPod{PodID: podID})
+			}
+			if ctx.ExpectingResponse() {
+				ctx.Respond(errors.Wrapf(err, "failed to start rank %d of pod group", rank))
+			}
+			return nil
+		}
+		started = append(started, cproto.ID(spec.ContainerID))
+	}
+	return nil
+}
+
+// validatePodGroupPlacement greedily places each member of a pod group onto the free slots
+// of the current nodes, returning a PodGroupPlacementError listing the ranks that could not
+// be placed. The check is a best-effort free-slot count that runs before any kubernetes
+// resources are created; it does not model affinity or namespace-level constraints, which
+// are covered by the per-rank rollback in receiveStartTaskPodGroup.
+func (p *pods) validatePodGroupPlacement(ctx *actor.Context, numPods, slotsPerPod int) error {
+	available := p.availableNodeSlots(ctx)
+
+	var unplaceableRanks []int
+	for rank := 0; rank < numPods; rank++ {
+		placed := false
+		for nodeName, freeSlots := range available {
+			if freeSlots >= int64(slotsPerPod) {
+				available[nodeName] = freeSlots - int64(slotsPerPod)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			unplaceableRanks = append(unplaceableRanks, rank)
+		}
 	}
 
+	if len(unplaceableRanks) > 0 {
+		return PodGroupPlacementError{UnplaceableRanks: unplaceableRanks}
+	}
 	return nil
 }
 
+func (p *pods) availableNodeSlots(ctx *actor.Context) map[string]int64 {
+	available := make(map[string]int64)
+	for nodeName, nodeSummary := range p.summarize(ctx) {
+		var freeSlots int64
+		for _, slot := range nodeSummary.Slots {
+			if slot.Container == nil {
+				freeSlots++
+			}
+		}
+		available[nodeName] = freeSlots
+	}
+	return available
+}
+
 func (p *pods) receiveJobQueueMsg(ctx *actor.Context) {
 	switch msg := ctx.Message().(type) {
 	case SetPodOrder:
 		if msg.QPosition > 0 {
+			p.containerIDToQPosition[msg.PodID.String()] = msg.QPosition
 			podName, ok := p.containerIDToPodName[msg.PodID.String()]
 			if !ok {
 				ctx.Log().WithField("pod-id", msg.PodID).Debug(
 					"received change position command for unregistered container id")
 				return
 			}
+			if p.terminatingPods[podName] {
+				ctx.Log().WithField("pod-name", podName).Debug(
+					"ignoring change position command for terminating pod")
+				return
+			}
 			// check that the pod exists
-			_, err := p.clientSet.CoreV1().Pods("default").Get(context.TODO(), podName, metaV1.GetOptions{})
+			_, err := p.podInterfaceFor(p.namespaceFor(podName)).Get(
+				context.TODO(), podName, metaV1.GetOptions{})
 			if err != nil {
 				ctx.Log().WithField("pod-id", msg.PodID).Info(
 					"change position command failed with err: ", err)
 			}
 
-			payload := []patchStringValue{{
-				Op:    "replace",
-				Path:  "/metadata/labels/determined-queue-position",
-				Value: fmt.Sprintf("%f", msg.QPosition),
-			}}
+			if err := p.patchPodQueuePosition(podName, msg.QPosition); err != nil {
+				ctx.Log().Infof("Failed to set the order of pod %s: ", podName)
+			}
+		}
+	}
+}
+
+// namespaceFor returns the namespace a tracked pod lives in, preferring the record
+// persisted at submission over the watch cache, which can lag for just-submitted pods.
+func (p *pods) namespaceFor(podName string) string {
+	if taskPod, ok := p.podNameToTaskPod[podName]; ok && taskPod.Namespace != "" {
+		return taskPod.Namespace
+	}
+	if namespace, ok := p.clusterPods[podName]; ok && namespace != "" {
+		return namespace
+	}
+	return p.namespace
+}
 
-			payloadBytes, _ := json.Marshal(payload)
+// patchPodQueuePosition stamps the queue position label onto a pod so the scheduler
+// observes its new order.
+func (p *pods) patchPodQueuePosition(podName string, qPosition float64) error {
+	payload := []patchStringValue{{
+		Op:    "replace",
+		Path:  "/metadata/labels/" + determinedQueuePositionLabel,
+		Value: fmt.Sprintf("%f", qPosition),
+	}}
+	payloadBytes, _ := json.Marshal(payload)
+
+	_, err := p.podInterfaceFor(p.namespaceFor(podName)).Patch(
+		context.TODO(), podName, types.JSONPatchType, payloadBytes, metaV1.PatchOptions{},
+	)
+	return err
+}
 
-			_, err = p.clientSet.CoreV1().Pods("default").Patch(
-				context.TODO(), podName, types.JSONPatchType, payloadBytes, metaV1.PatchOptions{},
-			)
-			if err != nil {
-				ctx.Log().Infof("Failed to set the order of pod %s: ", podName)
+// receiveSetPodGroupOrder repositions every pod of a task actor to the same queue
+// position. The patches are issued within a single message handler, so no other queue
+// mutation can interleave and leave the gang's ranks at inconsistent positions.
+func (p *pods) receiveSetPodGroupOrder(ctx *actor.Context, msg SetPodGroupOrder) {
+	repositioned := 0
+	if msg.QPosition > 0 {
+		for _, metadata := range p.podHandlerToMetadata {
+			if metadata.taskActor != msg.TaskActor {
+				continue
+			}
+			if p.terminatingPods[metadata.podName] {
+				ctx.Log().WithField("pod-name", metadata.podName).Debug(
+					"skipping terminating pod during group reorder")
+				continue
 			}
+			p.containerIDToQPosition[metadata.containerID] = msg.QPosition
+			if err := p.patchPodQueuePosition(metadata.podName, msg.QPosition); err != nil {
+				ctx.Log().WithField("pod-name", metadata.podName).WithError(err).Error(
+					"failed to set the order of pod during group reorder")
+				continue
+			}
+			repositioned++
 		}
 	}
+
+	ctx.Log().WithField("task-actor", msg.TaskActor.Address()).Infof(
+		"repositioned %d pods to queue position %f", repositioned, msg.QPosition)
+	if ctx.ExpectingResponse() {
+		ctx.Respond(repositioned)
+	}
 }
 
 func (p *pods) receivePodStatusUpdate(ctx *actor.Context, msg podStatusUpdate) {
+	if msg.deleted {
+		delete(p.clusterPods, msg.updatedPod.Name)
+		delete(p.podStatuses, msg.updatedPod.Name)
+	} else {
+		p.clusterPods[msg.updatedPod.Name] = msg.updatedPod.Namespace
+		restarts := int32(0)
+		for _, status := range msg.updatedPod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		p.podStatuses[msg.updatedPod.Name] = trackedPodStatus{
+			status: PodStatus{
+				PodName:      msg.updatedPod.Name,
+				Phase:        string(msg.updatedPod.Status.Phase),
+				Reason:       msg.updatedPod.Status.Reason,
+				NodeName:     msg.updatedPod.Spec.NodeName,
+				RestartCount: restarts,
+			},
+			createdAt: msg.updatedPod.ObjectMeta.CreationTimestamp.Time,
+		}
+	}
+	if msg.updatedPod.ObjectMeta.DeletionTimestamp != nil {
+		p.terminatingPods[msg.updatedPod.Name] = true
+	}
+
 	ref, ok := p.podNameToPodHandler[msg.updatedPod.Name]
 	if !ok {
+		// Job-mode tasks are tracked under their job name until the job controller
+		// creates the pod; adopt the pod by renaming the tracking entries, so
+		// reconciliation does not count the job name and the pod name as two pods.
+		if jobName := msg.updatedPod.ObjectMeta.Labels["job-name"]; jobName != "" {
+			if jobRef, tracked := p.podNameToPodHandler[jobName]; tracked {
+				ctx.Log().WithField("job-name", jobName).Infof(
+					"adopting pod %s created by job", msg.updatedPod.Name)
+				p.receivePodRescheduled(ctx, podRescheduled{
+					oldName: jobName, newName: msg.updatedPod.Name,
+				})
+				ctx.Tell(jobRef, jobPodAdopted{podName: msg.updatedPod.Name})
+				ref, ok = jobRef, true
+			}
+		}
+	}
+	if !ok {
+		if _, restored := p.podNameToTaskPod[msg.updatedPod.Name]; restored {
+			switch msg.updatedPod.Status.Phase {
+			case k8sV1.PodSucceeded, k8sV1.PodFailed:
+				p.forgetTaskPod(ctx, msg.updatedPod.Name)
+			}
+			return
+		}
 		ctx.Log().WithField("pod-name", msg.updatedPod.Name).Warn(
 			"received pod status update for un-registered pod")
 		return
 	}
 
+	if reason, evicted := p.detectNodeEviction(msg.updatedPod); evicted {
+		ctx.Tell(ref, podEvicted{reason: reason})
+	}
 	ctx.Tell(ref, msg)
 
+	p.recordSchedulingTimes(msg.updatedPod)
+
 	if containerID, ok := p.podNameToContainerID[msg.updatedPod.Name]; ok {
 		if state, ok := p.containerIDToSchedulingState[containerID]; ok {
 			currState := job.SchedulingStateQueued
@@ -436,46 +1221,1031 @@ func (p *pods) receivePodStatusUpdate(ctx *actor.Context, msg podStatusUpdate) {
 	}
 }
 
+// detectNodeEviction reports whether a pod status update looks like the node controller
+// evicting the pod from a lost node, e.g., a reclaimed spot instance: the pod is being
+// deleted while its node is NotReady or gone, or the pod was marked NodeLost.
+func (p *pods) detectNodeEviction(pod *k8sV1.Pod) (string, bool) {
+	if pod.Status.Reason == "NodeLost" {
+		return fmt.Sprintf("node %s was lost", pod.Spec.NodeName), true
+	}
+	if pod.ObjectMeta.DeletionTimestamp == nil || pod.Spec.NodeName == "" {
+		return "", false
+	}
+
+	node, ok := p.currentNodes[pod.Spec.NodeName]
+	if !ok {
+		return fmt.Sprintf("node %s no longer exists", pod.Spec.NodeName), true
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == k8sV1.NodeReady && condition.Status != k8sV1.ConditionTrue {
+			return fmt.Sprintf("node %s is not ready: %s",
+				pod.Spec.NodeName, condition.Reason), true
+		}
+	}
+	return "", false
+}
+
 func (p *pods) receiveNodeStatusUpdate(ctx *actor.Context, msg nodeStatusUpdate) {
 	if msg.updatedNode != nil {
+		previousNode, known := p.currentNodes[msg.updatedNode.Name]
 		p.currentNodes[msg.updatedNode.Name] = msg.updatedNode
+
+		// Kubernetes takes several minutes to mark a lost node's pods as failed, so
+		// notify the handlers of pods on the node as soon as it turns NotReady rather
+		// than waiting for the node controller to evict them.
+		if known && nodeIsReady(previousNode) && !nodeIsReady(msg.updatedNode) {
+			p.notifyPodsOnLostNode(ctx, msg.updatedNode.Name,
+				fmt.Sprintf("node %s is not ready", msg.updatedNode.Name))
+		}
 	}
 
 	if msg.deletedNode != nil {
 		delete(p.currentNodes, msg.deletedNode.Name)
+		p.notifyPodsOnLostNode(ctx, msg.deletedNode.Name,
+			fmt.Sprintf("node %s was removed from the cluster", msg.deletedNode.Name))
 	}
 }
 
-func (p *pods) receivePodEventUpdate(ctx *actor.Context, msg podEventUpdate) {
-	ref, ok := p.podNameToPodHandler[msg.event.InvolvedObject.Name]
-	if !ok {
-		// We log at the debug level because we are unable to filter
-		// pods based on their labels the way we do with pod status updates.
-		ctx.Log().WithField("pod-name", msg.event.InvolvedObject.Name).Debug(
-			"received pod event for an un-registered pod")
-		return
+// nodeIsReady reports whether the node's Ready condition is true.
+func nodeIsReady(node *k8sV1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == k8sV1.NodeReady {
+			return condition.Status == k8sV1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// notifyPodsOnLostNode tells the handler of every pod placed on the given node that its
+// pod is being lost to a node failure.
+func (p *pods) notifyPodsOnLostNode(ctx *actor.Context, nodeName string, reason string) {
+	podHandlers := make([]*actor.Ref, 0, len(p.podNameToPodHandler))
+	for _, podHandler := range p.podNameToPodHandler {
+		podHandlers = append(podHandlers, podHandler)
+	}
+	results := ctx.AskAll(getPodNodeInfo{}, podHandlers...).GetAll()
+
+	for ref, result := range results {
+		info := result.(podNodeInfo)
+		if info.nodeName != nodeName {
+			continue
+		}
+		ctx.Tell(ref, podEvicted{reason: reason})
+	}
+}
+
+// nodePoolOfNode returns the node pool label of the given node, or an empty string if
+// the node is unknown or unlabeled.
+func (p *pods) nodePoolOfNode(nodeName string) string {
+	node, ok := p.currentNodes[nodeName]
+	if !ok {
+		return ""
+	}
+	return node.Labels[determinedNodePoolLabel]
+}
+
+// recordSchedulingTimes observes the scheduling latency histograms as the pod passes
+// through the scheduled and running phases of its startup.
+func (p *pods) recordSchedulingTimes(pod *k8sV1.Pod) {
+	times, ok := p.podSchedulingTimes[pod.Name]
+	if !ok {
+		return
+	}
+	nodePool := p.nodePoolOfNode(pod.Spec.NodeName)
+
+	if times.scheduled.IsZero() {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == k8sV1.PodScheduled && condition.Status == k8sV1.ConditionTrue {
+				times.scheduled = time.Now().UTC()
+				prom.ObservePodQueueWait(nodePool, times.scheduled.Sub(times.submitted))
+				break
+			}
+		}
+	}
+
+	if !times.running && pod.Status.Phase == k8sV1.PodRunning {
+		times.running = true
+		prom.ObservePodStartup(nodePool, time.Since(times.submitted))
+	}
+}
+
+// recordImagePullTimes observes the image pull histogram from the Pulling and Pulled
+// events kubernetes emits while fetching a pod's images.
+func (p *pods) recordImagePullTimes(msg podEventUpdate) {
+	times, ok := p.podSchedulingTimes[msg.event.InvolvedObject.Name]
+	if !ok {
+		return
+	}
+
+	switch msg.event.Reason {
+	case "Pulling":
+		if times.pulling.IsZero() {
+			times.pulling = time.Now().UTC()
+		}
+	case "Pulled":
+		if !times.pulling.IsZero() {
+			// The source host of a kubelet event is the node pulling the image.
+			prom.ObservePodImagePull(
+				p.nodePoolOfNode(msg.event.Source.Host), time.Since(times.pulling))
+			times.pulling = time.Time{}
+		}
+	}
+}
+
+func (p *pods) receivePodEventUpdate(ctx *actor.Context, msg podEventUpdate) {
+	ref, ok := p.podNameToPodHandler[msg.event.InvolvedObject.Name]
+	if !ok {
+		// We log at the debug level because we are unable to filter
+		// pods based on their labels the way we do with pod status updates.
+		ctx.Log().WithField("pod-name", msg.event.InvolvedObject.Name).Debug(
+			"received pod event for an un-registered pod")
+		return
 	}
 
 	ctx.Tell(ref, msg)
+
+	p.recordImagePullTimes(msg)
+}
+
+func (p *pods) receiveResourceSummarize(ctx *actor.Context, msg SummarizeResources) {
+	summary := p.summarize(ctx)
+	slots := 0
+	for _, node := range summary {
+		slots += len(node.Slots)
+	}
+	ctx.Respond(&PodsInfo{NumAgents: len(summary), SlotsAvailable: slots})
+}
+
+func (p *pods) receiveGetResourceSummary(ctx *actor.Context, msg GetResourceSummary) {
+	podHandlers := make([]*actor.Ref, 0, len(p.podNameToPodHandler))
+	for _, podHandler := range p.podNameToPodHandler {
+		podHandlers = append(podHandlers, podHandler)
+	}
+	results := ctx.AskAll(getPodNodeInfo{}, podHandlers...).GetAll()
+
+	type poolKey struct{ nodePool, gpuType string }
+	entries := make(map[poolKey]*ResourcePoolSummary)
+	entryFor := func(k poolKey) *ResourcePoolSummary {
+		if _, ok := entries[k]; !ok {
+			entries[k] = &ResourcePoolSummary{NodePool: k.nodePool, GPUType: k.gpuType}
+		}
+		return entries[k]
+	}
+
+	nodeToPool := make(map[string]poolKey, len(p.currentNodes))
+	for _, node := range p.currentNodes {
+		k := poolKey{node.Labels[determinedNodePoolLabel], node.Labels[p.gpuTypeNodeLabel]}
+		entryFor(k).TotalSlots += int(p.nodeSlotCount(node))
+		nodeToPool[node.Name] = k
+	}
+
+	for _, result := range results {
+		info := result.(podNodeInfo)
+		if len(info.nodeName) == 0 {
+			// Pending pods have not been assigned a node, so their demand cannot be
+			// attributed to a node pool yet.
+			if info.sharedSlots {
+				entryFor(poolKey{"", info.gpuType}).PendingSharedSlots += info.numSlots
+			} else {
+				entryFor(poolKey{"", info.gpuType}).PendingSlots += info.numSlots
+			}
+			continue
+		}
+		k, ok := nodeToPool[info.nodeName]
+		if !ok {
+			continue
+		}
+		if info.sharedSlots {
+			entryFor(k).AllocatedSharedSlots += info.numSlots
+		} else {
+			entryFor(k).AllocatedSlots += info.numSlots
+		}
+	}
+
+	summary := ResourceSummary{
+		Timestamp: time.Now().UTC(),
+		Staleness: time.Since(p.lastReconcile),
+	}
+	for _, entry := range entries {
+		summary.Pools = append(summary.Pools, *entry)
+	}
+	sort.Slice(summary.Pools, func(i, j int) bool {
+		if summary.Pools[i].NodePool != summary.Pools[j].NodePool {
+			return summary.Pools[i].NodePool < summary.Pools[j].NodePool
+		}
+		return summary.Pools[i].GPUType < summary.Pools[j].GPUType
+	})
+
+	if ctx.ExpectingResponse() {
+		ctx.Respond(summary)
+	}
+}
+
+func (p *pods) receiveGetPodLogs(ctx *actor.Context, msg GetPodLogs) {
+	podName, ok := p.containerIDToPodName[msg.PodID.String()]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received pod logs request for unregistered container id")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod found for container %s", msg.PodID))
+		}
+		return
+	}
+	ref, ok := p.podNameToPodHandler[podName]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received pod logs request for unregistered pod")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod handler found for pod %s", podName))
+		}
+		return
+	}
+
+	response := ctx.Ask(ref, msg).Get()
+	if ctx.ExpectingResponse() && response != nil {
+		ctx.Respond(response)
+	}
+}
+
+func (p *pods) receiveGetPodPlacement(ctx *actor.Context, msg GetPodPlacement) {
+	podName, ok := p.containerIDToPodName[msg.PodID.String()]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received pod placement request for unregistered container id")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod found for container %s", msg.PodID))
+		}
+		return
+	}
+	ref, ok := p.podNameToPodHandler[podName]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received pod placement request for unregistered pod")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod handler found for pod %s", podName))
+		}
+		return
+	}
+
+	response := ctx.Ask(ref, msg).Get()
+	if ctx.ExpectingResponse() && response != nil {
+		ctx.Respond(response)
+	}
+}
+
+// receiveGetPodStatuses serves a bulk status read for many pods from the watch-derived
+// status cache, so dashboards get one round trip instead of a request per pod.
+func (p *pods) receiveGetPodStatuses(ctx *actor.Context, msg GetPodStatuses) {
+	now := time.Now()
+	statuses := make(map[cproto.ID]PodStatus, len(msg.PodIDs))
+
+	addStatus := func(containerID string) {
+		podName, ok := p.containerIDToPodName[containerID]
+		if !ok {
+			return
+		}
+		tracked, ok := p.podStatuses[podName]
+		if !ok {
+			return
+		}
+		status := tracked.status
+		if !tracked.createdAt.IsZero() {
+			status.Age = now.Sub(tracked.createdAt)
+		}
+		statuses[cproto.ID(containerID)] = status
+	}
+
+	if len(msg.PodIDs) == 0 {
+		for containerID := range p.containerIDToPodName {
+			addStatus(containerID)
+		}
+	} else {
+		for _, podID := range msg.PodIDs {
+			addStatus(podID.String())
+		}
+	}
+
+	if ctx.ExpectingResponse() {
+		ctx.Respond(statuses)
+	}
+}
+
+// podStatsCacheDuration is how long a pod metrics sample is served from the cache before
+// the metrics API is queried again, to avoid hammering the API server.
+const podStatsCacheDuration = 5 * time.Second
+
+func (p *pods) receiveGetPodStats(ctx *actor.Context, msg GetPodStats) {
+	podName, ok := p.containerIDToPodName[msg.PodID.String()]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received pod stats request for unregistered container id")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(PodStatsNotReadyError{PodID: msg.PodID})
+		}
+		return
+	}
+
+	if stats, ok := p.podStatsCache[podName]; ok &&
+		time.Since(stats.Timestamp) < podStatsCacheDuration {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(stats)
+		}
+		return
+	}
+
+	podMetrics, err := p.podMetricsInterface.Get(context.TODO(), podName, metaV1.GetOptions{})
+	if err != nil {
+		ctx.Log().WithField("pod-name", podName).WithError(err).Debug(
+			"failed to fetch pod metrics")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(PodStatsNotReadyError{PodID: msg.PodID})
+		}
+		return
+	}
+
+	stats := PodStats{Timestamp: podMetrics.Timestamp.Time}
+	for _, container := range podMetrics.Containers {
+		stats.CPUMillicores += container.Usage.Cpu().MilliValue()
+		stats.MemoryBytes += container.Usage.Memory().Value()
+	}
+
+	if stats.Timestamp.IsZero() {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(PodStatsNotReadyError{PodID: msg.PodID})
+		}
+		return
+	}
+
+	p.podStatsCache[podName] = stats
+	if ctx.ExpectingResponse() {
+		ctx.Respond(stats)
+	}
+}
+
+func (p *pods) receiveGetPodEvents(ctx *actor.Context, msg GetPodEvents) {
+	podName, ok := p.containerIDToPodName[msg.PodID.String()]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received pod events request for unregistered container id")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod found for container %s", msg.PodID))
+		}
+		return
+	}
+
+	namespace, ok := p.clusterPods[podName]
+	if !ok || namespace == "" {
+		namespace = p.namespace
+	}
+
+	events, err := p.clientSet.CoreV1().Events(namespace).List(
+		context.TODO(), metaV1.ListOptions{
+			FieldSelector: fmt.Sprintf("involvedObject.name=%s", podName),
+		})
+	if err != nil {
+		ctx.Log().WithField("pod-name", podName).WithError(err).Error(
+			"error listing pod events")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Wrapf(err, "error listing events for pod %s", podName))
+		}
+		return
+	}
+
+	podEvents := make([]PodEvent, 0, len(events.Items))
+	for _, event := range events.Items {
+		timestamp := event.LastTimestamp.Time
+		if timestamp.IsZero() {
+			timestamp = event.CreationTimestamp.Time
+		}
+		if msg.Since != nil && timestamp.Before(*msg.Since) {
+			continue
+		}
+
+		podEvents = append(podEvents, PodEvent{
+			Type:      event.Type,
+			Reason:    event.Reason,
+			Message:   event.Message,
+			Count:     event.Count,
+			Timestamp: timestamp,
+		})
+	}
+	sort.Slice(podEvents, func(i, j int) bool {
+		return podEvents[i].Timestamp.Before(podEvents[j].Timestamp)
+	})
+
+	if ctx.ExpectingResponse() {
+		ctx.Respond(podEvents)
+	}
+}
+
+// reconcileRelistPeriod is the minimum interval between the full LISTs of cluster pods
+// that reconciliation uses to heal its watch-derived cache from missed watch events.
+// Between relists, reconciliation is served entirely from the cache.
+const reconcileRelistPeriod = 5 * time.Minute
+
+// relistClusterPods rebuilds the watch-derived cache of cluster pods from a full LIST.
+// It is rate limited by relistRateLimiter; in steady state the cache is maintained from
+// watch events alone and no LIST calls are made.
+func (p *pods) relistClusterPods(ctx *actor.Context) error {
+	clusterPods, err := p.clientSet.CoreV1().Pods(metaV1.NamespaceAll).List(
+		context.TODO(), metaV1.ListOptions{LabelSelector: determinedLabel})
+	if err != nil {
+		return errors.Wrap(err, "error listing cluster pods for reconcile state")
+	}
+
+	p.clusterPods = make(map[string]string, len(clusterPods.Items))
+	for _, pod := range clusterPods.Items {
+		p.clusterPods[pod.Name] = pod.Namespace
+	}
+	return nil
+}
+
+// reconcilePodStates compares the tracked pods against the pods present in the cluster.
+func reconcilePodStates(
+	tracked map[string]string, inCluster map[string]bool,
+) map[string]PodReconcileStatus {
+	statuses := make(map[string]PodReconcileStatus)
+	for podName := range tracked {
+		if inCluster[podName] {
+			statuses[podName] = PodPresent
+		} else {
+			statuses[podName] = PodMissing
+		}
+	}
+	for podName := range inCluster {
+		if _, ok := tracked[podName]; !ok {
+			statuses[podName] = PodUnexpected
+		}
+	}
+	return statuses
+}
+
+func (p *pods) receiveGetReconcileState(ctx *actor.Context, msg GetReconcileState) {
+	if p.relistRateLimiter.TryAccept() {
+		if err := p.relistClusterPods(ctx); err != nil {
+			ctx.Log().WithError(err).Warn(
+				"error relisting cluster pods; serving reconcile state from the watch cache")
+		}
+	}
+
+	inCluster := make(map[string]bool, len(p.clusterPods))
+	for podName, namespace := range p.clusterPods {
+		if !p.taskNamespaces[namespace] {
+			continue
+		}
+		inCluster[podName] = true
+	}
+
+	state := ReconcileState{Pods: reconcilePodStates(p.podNameToContainerID, inCluster)}
+	p.lastReconcile = time.Now().UTC()
+	state.LastReconcile = p.lastReconcile
+	if ctx.ExpectingResponse() {
+		ctx.Respond(state)
+	}
+}
+
+// trackedPodNamespace returns the namespace a tracked pod was started in, falling back
+// to the resource manager's default namespace for pods without a recorded start message.
+func (p *pods) trackedPodNamespace(podName string) string {
+	if start, ok := p.startMessages[podName]; ok && start.Spec.Namespace != "" {
+		return start.Spec.Namespace
+	}
+	return p.namespace
+}
+
+func (p *pods) receiveResyncPods(ctx *actor.Context, msg ResyncPods) {
+	namespace := metaV1.NamespaceAll
+	if msg.Namespace != "" {
+		namespace = msg.Namespace
+	}
+
+	// An explicit resync bypasses the relist rate limiter; it is an operator-triggered
+	// recovery action, e.g., after a partition between the master and the API server.
+	clusterPods, err := p.clientSet.CoreV1().Pods(namespace).List(
+		context.TODO(), metaV1.ListOptions{LabelSelector: determinedLabel})
+	if err != nil {
+		ctx.Log().WithError(err).Error("error listing cluster pods for resync")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Wrap(err, "error listing cluster pods for resync"))
+		}
+		return
+	}
+
+	// Heal the watch-derived cache from the fresh listing.
+	if msg.Namespace == "" {
+		p.clusterPods = make(map[string]string, len(clusterPods.Items))
+	} else {
+		for podName, podNamespace := range p.clusterPods {
+			if podNamespace == msg.Namespace {
+				delete(p.clusterPods, podName)
+			}
+		}
+	}
+	for _, pod := range clusterPods.Items {
+		p.clusterPods[pod.Name] = pod.Namespace
+	}
+
+	statuses := make(map[string]PodReconcileStatus)
+	for i := range clusterPods.Items {
+		pod := &clusterPods.Items[i]
+		if _, tracked := p.podNameToPodHandler[pod.Name]; tracked {
+			statuses[pod.Name] = PodPresent
+			continue
+		}
+		if _, restored := p.podNameToTaskPod[pod.Name]; restored {
+			statuses[pod.Name] = PodPresent
+			continue
+		}
+
+		// Pods the job controller created for a tracked job-mode task are adopted by
+		// replaying the status update that was missed; everything else determined-labeled
+		// and untracked is killed.
+		jobName := pod.ObjectMeta.Labels["job-name"]
+		if _, tracked := p.podNameToPodHandler[jobName]; jobName != "" && tracked {
+			p.receivePodStatusUpdate(ctx, podStatusUpdate{updatedPod: pod})
+			statuses[pod.Name] = PodAdopted
+			continue
+		}
+
+		ctx.Log().WithField("pod-name", pod.Name).Warn(
+			"killing unexpected determined-labeled pod found during resync")
+		err := p.clientSet.CoreV1().Pods(pod.Namespace).Delete(
+			context.TODO(), pod.Name, metaV1.DeleteOptions{})
+		if err != nil {
+			ctx.Log().WithField("pod-name", pod.Name).WithError(err).Error(
+				"failed to delete unexpected pod during resync")
+		}
+		statuses[pod.Name] = PodUnexpected
+	}
+
+	for podName := range p.podNameToContainerID {
+		if msg.Namespace != "" && p.trackedPodNamespace(podName) != msg.Namespace {
+			continue
+		}
+		if _, ok := statuses[podName]; ok {
+			continue
+		}
+		ctx.Log().WithField("pod-name", podName).Warn("tracked pod is missing from the cluster")
+		statuses[podName] = PodMissing
+	}
+
+	p.lastReconcile = time.Now().UTC()
+	if ctx.ExpectingResponse() {
+		ctx.Respond(ReconcileState{Pods: statuses, LastReconcile: p.lastReconcile})
+	}
+}
+
+func (p *pods) receiveGetQueuePosition(ctx *actor.Context, msg GetQueuePosition) {
+	podName, ok := p.containerIDToPodName[msg.PodID.String()]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received queue position request for unregistered container id")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod found for container %s", msg.PodID))
+		}
+		return
+	}
+
+	if p.containerIDToSchedulingState[msg.PodID.String()] == job.SchedulingStateScheduled {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(QueuePosition{Scheduled: true})
+		}
+		return
+	}
+
+	pendingPods, err := p.clientSet.CoreV1().Pods(metaV1.NamespaceAll).List(
+		context.TODO(), metaV1.ListOptions{
+			LabelSelector: determinedLabel,
+			FieldSelector: "status.phase=Pending",
+		})
+	if err != nil {
+		ctx.Log().WithError(err).Error("error listing pending pods for queue position")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Wrap(err, "error listing pending pods for queue position"))
+		}
+		return
+	}
+
+	pending := make([]k8sV1.Pod, 0, len(pendingPods.Items))
+	for _, pod := range pendingPods.Items {
+		if p.taskNamespaces[pod.Namespace] {
+			pending = append(pending, pod)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return schedulingOrderLess(&pending[i], &pending[j])
+	})
+
+	position := QueuePosition{TotalPending: len(pending)}
+	for i := range pending {
+		if pending[i].Name == podName {
+			position.Position = i + 1
+			break
+		}
+		position.SlotsAhead += p.podSlotsDemand(&pending[i])
+	}
+	if position.Position == 0 {
+		// The pod has not appeared in the list yet, e.g., because its creation request
+		// is still queued; report it at the back of the queue.
+		position.TotalPending++
+		position.Position = position.TotalPending
+	}
+
+	if ctx.ExpectingResponse() {
+		ctx.Respond(position)
+	}
+}
+
+// schedulingOrderLess orders pending pods the same way the scheduler considers them:
+// descending priority first, then ascending queue position as set by SetPodOrder, then
+// creation time.
+func schedulingOrderLess(a, b *k8sV1.Pod) bool {
+	var aPriority, bPriority int32
+	if a.Spec.Priority != nil {
+		aPriority = *a.Spec.Priority
+	}
+	if b.Spec.Priority != nil {
+		bPriority = *b.Spec.Priority
+	}
+	if aPriority != bPriority {
+		return aPriority > bPriority
+	}
+
+	aPosition, bPosition := queuePositionOf(a), queuePositionOf(b)
+	if aPosition != bPosition {
+		return aPosition < bPosition
+	}
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
 }
 
-func (p *pods) receiveResourceSummarize(ctx *actor.Context, msg SummarizeResources) {
-	summary := p.summarize(ctx)
+// queuePositionOf returns the queue position label of a pod, or the maximum value for
+// pods that have never been assigned one so they sort behind explicitly ordered pods.
+func queuePositionOf(pod *k8sV1.Pod) float64 {
+	value, ok := pod.Labels[determinedQueuePositionLabel]
+	if !ok {
+		return math.MaxFloat64
+	}
+	position, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return math.MaxFloat64
+	}
+	return position
+}
+
+// podSlotsDemand estimates the number of slots a pending pod will occupy once scheduled.
+func (p *pods) podSlotsDemand(pod *k8sV1.Pod) int {
 	slots := 0
-	for _, node := range summary {
-		slots += len(node.Slots)
+	for _, container := range pod.Spec.Containers {
+		switch p.slotType {
+		case device.CPU:
+			if p.slotResourceRequests.CPU > 0 {
+				milliCPUs := container.Resources.Requests.Cpu().MilliValue()
+				slots += int(float32(milliCPUs) / (1000. * p.slotResourceRequests.CPU))
+			}
+		default:
+			if gpus, ok := container.Resources.Limits["nvidia.com/gpu"]; ok {
+				slots += int(gpus.Value())
+			}
+		}
+	}
+	return slots
+}
+
+func (p *pods) receiveSubscribeToPodEvents(ctx *actor.Context, msg SubscribeToPodEvents) {
+	if msg.Subscriber == nil {
+		ctx.Log().Warn("received pod event subscription with no subscriber")
+		return
+	}
+	if p.podStateSubscribers[msg.Subscriber] {
+		return
+	}
+
+	p.podStateSubscribers[msg.Subscriber] = true
+	actors.NotifyOnStop(ctx, msg.Subscriber, podEventSubscriberStopped{subscriber: msg.Subscriber})
+}
+
+func (p *pods) receivePodStateChanged(ctx *actor.Context, msg PodStateChanged) {
+	switch msg.NewState {
+	case cproto.Running:
+		p.resolveRunningWaiters(msg.PodID.String(), nil)
+	case cproto.Terminated:
+		p.resolveRunningWaiters(msg.PodID.String(), PodFailedError{PodID: msg.PodID})
+	}
+
+	for subscriber := range p.podStateSubscribers {
+		ctx.Tell(subscriber, msg)
+	}
+}
+
+func (p *pods) receiveWaitForPodRunning(ctx *actor.Context, msg WaitForPodRunning) {
+	podName, ok := p.containerIDToPodName[msg.PodID.String()]
+	if !ok {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod found for container %s", msg.PodID))
+		}
+		return
+	}
+
+	// The caller blocks on the channel, so it must be handed back before the waiter is
+	// resolved; the buffer lets the resolution be delivered without a reader attached.
+	ch := make(chan error, 1)
+	if ctx.ExpectingResponse() {
+		ctx.Respond((<-chan error)(ch))
+	}
+
+	// Resolve immediately from the pod's current state if the transition being waited
+	// on has already happened.
+	if ref, ok := p.podNameToPodHandler[podName]; ok {
+		if info, ok := ctx.Ask(ref, getPodNodeInfo{}).Get().(podNodeInfo); ok {
+			switch info.container.State {
+			case cproto.Running:
+				ch <- nil
+				return
+			case cproto.Terminated:
+				ch <- PodFailedError{PodID: msg.PodID}
+				return
+			}
+		}
+	}
+
+	p.runningWaiters[msg.PodID.String()] = append(p.runningWaiters[msg.PodID.String()], ch)
+	actors.NotifyAfter(ctx, msg.Timeout, runningWaitTimeout{
+		podID: msg.PodID.String(), timeout: msg.Timeout, ch: ch,
+	})
+}
+
+func (p *pods) receiveRunningWaitTimeout(ctx *actor.Context, msg runningWaitTimeout) {
+	waiters := p.runningWaiters[msg.podID]
+	for i, ch := range waiters {
+		if ch != msg.ch {
+			continue
+		}
+		ch <- PodTimedOutError{PodID: cproto.ID(msg.podID), Timeout: msg.timeout}
+		p.runningWaiters[msg.podID] = append(waiters[:i], waiters[i+1:]...)
+		if len(p.runningWaiters[msg.podID]) == 0 {
+			delete(p.runningWaiters, msg.podID)
+		}
+		return
+	}
+}
+
+// resolveRunningWaiters resolves every WaitForPodRunning waiter of the given container
+// with err and deregisters them.
+func (p *pods) resolveRunningWaiters(podID string, err error) {
+	for _, ch := range p.runningWaiters[podID] {
+		ch <- err
+	}
+	delete(p.runningWaiters, podID)
+}
+
+func (p *pods) receiveSetPodResources(ctx *actor.Context, msg SetPodResources) {
+	podName, ok := p.containerIDToPodName[msg.PodID.String()]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received set pod resources command for unregistered container id")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod found for container %s", msg.PodID))
+		}
+		return
+	}
+
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []map[string]interface{}{{
+				"name": model.DeterminedK8ContainerName,
+				"resources": map[string]interface{}{
+					"requests": map[string]string{
+						"cpu":    msg.CPU.String(),
+						"memory": msg.Memory.String(),
+					},
+				},
+			}},
+		},
+	}
+	payloadBytes, err := json.Marshal(patch)
+	if err != nil {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Wrap(err, "failed to marshal pod resize patch"))
+		}
+		return
+	}
+
+	namespace, ok := p.clusterPods[podName]
+	if !ok || namespace == "" {
+		namespace = p.namespace
+	}
+
+	// The resize subresource only exists on API servers with the in-place pod vertical
+	// scaling feature enabled; without it the patch fails and the error is passed back
+	// to the requester rather than applied on pod restart.
+	updatedPod, err := p.podInterfaceFor(namespace).Patch(
+		context.TODO(), podName, types.StrategicMergePatchType, payloadBytes,
+		metaV1.PatchOptions{}, "resize",
+	)
+	if err != nil {
+		ctx.Log().WithField("pod-name", podName).WithError(err).Info(
+			"failed to resize pod in place")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Wrapf(err,
+				"failed to resize pod %s in place; "+
+					"the cluster may not support in-place pod resize", podName))
+		}
+		return
+	}
+
+	// Echo back the requests the API server actually applied, since it may clamp them
+	// to LimitRange bounds.
+	applied := PodResources{}
+	for _, container := range updatedPod.Spec.Containers {
+		if container.Name != model.DeterminedK8ContainerName {
+			continue
+		}
+		applied.CPU = *container.Resources.Requests.Cpu()
+		applied.Memory = *container.Resources.Requests.Memory()
+	}
+	if ctx.ExpectingResponse() {
+		ctx.Respond(applied)
+	}
+}
+
+// receiveValidateTaskSpec builds the pod a task spec would create and submits it to the
+// API server as a dry run, so the full admission chain (quota, LimitRanges, admission
+// webhooks, referenced secrets) runs without creating anything.
+func (p *pods) receiveValidateTaskSpec(ctx *actor.Context, msg ValidateTaskSpec) {
+	namespace := p.namespace
+	if msg.Spec.Namespace != "" {
+		namespace = msg.Spec.Namespace
+	}
+
+	// newPod requires a parent task actor for the container it would monitor; the
+	// dry-run pod is never started as an actor, so the pods actor stands in when the
+	// requester did not name one.
+	taskActor := msg.TaskActor
+	if taskActor == nil {
+		taskActor = ctx.Self()
+	}
+
+	validationPod := newPod(
+		StartTaskPod{TaskActor: taskActor, Spec: msg.Spec, Slots: msg.Slots},
+		p.cluster, msg.Spec.ClusterID,
+		p.clientSet, namespace, p.masterIP, p.masterPort,
+		p.masterTLSConfig, p.loggingTLSConfig, p.loggingConfig,
+		p.podInterfaceFor(namespace), p.configMapInterfaceFor(namespace),
+		p.resourceRequestQueue, p.leaveKubernetesResources,
+		p.slotType, p.slotResourceRequests, p.scheduler, p.gpuTypeNodeLabel,
+		p.killGracePeriodSeconds, p.initTimeout, p.maxPendingTimeout, p.fluentConfig,
+		len(p.currentNodes),
+	)
+	if err := validationPod.createPodSpec(ctx, p.scheduler); err != nil {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(TaskSpecValidation{Errors: []string{err.Error()}})
+		}
+		return
+	}
+
+	_, err := p.podInterfaceFor(namespace).Create(
+		context.TODO(), validationPod.pod,
+		metaV1.CreateOptions{DryRun: []string{metaV1.DryRunAll}})
+	if err == nil {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(TaskSpecValidation{Valid: true})
+		}
+		return
+	}
+
+	statusErr, ok := err.(*k8error.StatusError)
+	if !ok {
+		// A transport failure says nothing about the validity of the spec.
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Wrap(err, "failed to reach the kubernetes api server for dry run"))
+		}
+		return
+	}
+
+	validation := TaskSpecValidation{}
+	if details := statusErr.ErrStatus.Details; details != nil {
+		for _, cause := range details.Causes {
+			if cause.Field != "" {
+				validation.Errors = append(
+					validation.Errors, fmt.Sprintf("%s: %s", cause.Field, cause.Message))
+				continue
+			}
+			validation.Errors = append(validation.Errors, cause.Message)
+		}
+	}
+	if len(validation.Errors) == 0 {
+		validation.Errors = append(validation.Errors, statusErr.ErrStatus.Message)
+	}
+	if ctx.ExpectingResponse() {
+		ctx.Respond(validation)
 	}
-	ctx.Respond(&PodsInfo{NumAgents: len(summary), SlotsAvailable: slots})
 }
 
 func (p *pods) receivePodPreemption(ctx *actor.Context, msg PreemptTaskPod) {
 	ref, ok := p.podNameToPodHandler[msg.PodName]
 	if !ok {
+		if _, restored := p.podNameToTaskPod[msg.PodName]; restored {
+			p.deleteRestoredPodResources(ctx, msg.PodName)
+			return
+		}
 		ctx.Log().WithField("pod-name", msg.PodName).Debug(
 			"received preemption command for unregistered pod")
 		return
 	}
+	if p.terminatingPods[msg.PodName] {
+		ctx.Log().WithField("pod-name", msg.PodName).Debug(
+			"ignoring preemption command for terminating pod")
+		return
+	}
 	ctx.Tell(ref, msg)
+
+	if msg.GracePeriodSeconds > 0 {
+		gracePeriod := time.Duration(msg.GracePeriodSeconds) * time.Second
+		deadline := time.Now().Add(gracePeriod)
+		// Overwriting any previous deadline resets the grace period rather than stacking
+		// timers; the superseded timer is ignored when it fires.
+		p.preemptionDeadlines[msg.PodName] = deadline
+		actors.NotifyAfter(ctx, gracePeriod, preemptionTimeout{
+			podName:  msg.PodName,
+			deadline: deadline,
+		})
+	}
+}
+
+func (p *pods) receiveDrainNode(ctx *actor.Context, msg DrainNode) {
+	if err := p.cordonNode(msg.NodeName); err != nil {
+		ctx.Log().WithField("node-name", msg.NodeName).WithError(err).Error(
+			"failed to cordon node")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Wrapf(err, "failed to cordon node %s", msg.NodeName))
+		}
+		return
+	}
+	ctx.Log().WithField("node-name", msg.NodeName).Info("draining node")
+
+	podHandlers := make([]*actor.Ref, 0, len(p.podNameToPodHandler))
+	for _, podHandler := range p.podNameToPodHandler {
+		podHandlers = append(podHandlers, podHandler)
+	}
+	results := ctx.AskAll(getPodNodeInfo{}, podHandlers...).GetAll()
+
+	evictedPodIDs := make([]cproto.ID, 0)
+	for ref, result := range results {
+		info := result.(podNodeInfo)
+		if info.nodeName != msg.NodeName {
+			continue
+		}
+
+		podInfo, ok := p.podHandlerToMetadata[ref]
+		if !ok {
+			continue
+		}
+
+		p.receivePodPreemption(ctx, PreemptTaskPod{
+			PodName:            podInfo.podName,
+			GracePeriodSeconds: int64(msg.GracePeriod.Seconds()),
+		})
+		evictedPodIDs = append(evictedPodIDs, cproto.ID(podInfo.containerID))
+	}
+
+	if ctx.ExpectingResponse() {
+		ctx.Respond(evictedPodIDs)
+	}
+}
+
+func (p *pods) cordonNode(nodeName string) error {
+	payload := []patchBoolValue{{
+		Op:    "replace",
+		Path:  "/spec/unschedulable",
+		Value: true,
+	}}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.clientSet.CoreV1().Nodes().Patch(
+		context.TODO(), nodeName, types.JSONPatchType, payloadBytes, metaV1.PatchOptions{},
+	)
+	return err
+}
+
+func (p *pods) receivePreemptionTimeout(ctx *actor.Context, msg preemptionTimeout) {
+	deadline, ok := p.preemptionDeadlines[msg.podName]
+	if !ok || !deadline.Equal(msg.deadline) {
+		// The pod exited before the deadline or the grace period was reset.
+		return
+	}
+	delete(p.preemptionDeadlines, msg.podName)
+
+	containerID, ok := p.podNameToContainerID[msg.podName]
+	if !ok {
+		return
+	}
+
+	ctx.Log().WithField("pod-name", msg.podName).Warn(
+		"force killing pod: it did not exit within its preemption grace period")
+	p.receiveKillPod(ctx, KillTaskPod{PodID: cproto.ID(containerID)})
 }
 
 func (p *pods) receivePriorityChange(ctx *actor.Context, msg ChangePriority) {
@@ -491,6 +2261,11 @@ func (p *pods) receivePriorityChange(ctx *actor.Context, msg ChangePriority) {
 			"received change priority command for unregistered container id")
 		return
 	}
+	if p.terminatingPods[podName] {
+		ctx.Log().WithField("pod-name", podName).Debug(
+			"ignoring change priority command for terminating pod")
+		return
+	}
 
 	ctx.Tell(ref, msg)
 }
@@ -508,39 +2283,254 @@ func (p *pods) receiveKillPod(ctx *actor.Context, msg KillTaskPod) {
 
 	ref, ok := p.podNameToPodHandler[name]
 	if !ok {
+		if _, restored := p.podNameToTaskPod[name]; restored {
+			p.deleteRestoredPodResources(ctx, name)
+			return
+		}
 		ctx.Log().WithField("pod-id", msg.PodID).Info(
 			"received stop pod command for unregistered container id")
 		return
 	}
 
+	p.terminatingPods[name] = true
 	ctx.Tell(ref, msg)
 }
 
-func (p *pods) cleanUpPodHandler(ctx *actor.Context, podHandler *actor.Ref) error {
-	podInfo, ok := p.podHandlerToMetadata[podHandler]
+func (p *pods) receiveKillAllPodsForTask(ctx *actor.Context, msg KillAllPodsForTask) {
+	// A zero grace period means the caller did not specify one; leaving the pointer nil
+	// lets each pod fall back to the configured kill grace period instead of being
+	// force deleted.
+	var gracePeriodSeconds *int64
+	if msg.GracePeriod > 0 {
+		seconds := int64(msg.GracePeriod.Seconds())
+		gracePeriodSeconds = &seconds
+	}
+
+	killed := make([]cproto.ID, 0)
+	for ref, metadata := range p.podHandlerToMetadata {
+		if metadata.taskActor != msg.TaskActor {
+			continue
+		}
+		p.terminatingPods[metadata.podName] = true
+		ctx.Tell(ref, KillTaskPod{
+			PodID:              cproto.ID(metadata.containerID),
+			GracePeriodSeconds: gracePeriodSeconds,
+		})
+		killed = append(killed, cproto.ID(metadata.containerID))
+	}
+
+	// Tasks with no pods left are not marked terminating, since there is no teardown for
+	// a StartTaskPod to race against and nothing would ever clear the mark.
+	if len(killed) > 0 {
+		p.terminatingTasks[msg.TaskActor] = true
+	}
+	ctx.Log().WithField("task-actor", msg.TaskActor.Address()).Infof(
+		"killing all %d pods of task", len(killed))
+
+	if ctx.ExpectingResponse() {
+		ctx.Respond(killed)
+	}
+}
+
+func (p *pods) receiveSuspendTaskPod(ctx *actor.Context, msg SuspendTaskPod) {
+	podName, ok := p.containerIDToPodName[msg.PodID.String()]
 	if !ok {
-		return errors.Errorf("unknown pod handler being deleted %s", podHandler.Address())
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received suspend command for unregistered container id")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod found for container %s", msg.PodID))
+		}
+		return
+	}
+	ref, ok := p.podNameToPodHandler[podName]
+	if !ok {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod handler found for pod %s", podName))
+		}
+		return
+	}
+	start, ok := p.startMessages[podName]
+	if !ok {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf(
+				"no start message recorded for pod %s; cannot suspend", podName))
+		}
+		return
 	}
 
-	name := fmt.Sprintf("%s-priorityclass", podInfo.containerID)
-	_, exists := p.clientSet.SchedulingV1().PriorityClasses().Get(
-		context.TODO(), name, metaV1.GetOptions{})
-	if exists == nil {
-		err := p.clientSet.SchedulingV1().PriorityClasses().Delete(
-			context.TODO(), name, metaV1.DeleteOptions{})
-		if err != nil {
-			ctx.Log().Warnf("Deletion of PriorityClass %s failed.", name)
+	p.suspendedPods[msg.PodID.String()] = suspendedPod{
+		start:     start,
+		qPosition: p.containerIDToQPosition[msg.PodID.String()],
+	}
+
+	// The checkpoint is recorded; confirm it before removing the pod so the caller knows
+	// the pod is resumable even if the deletion below races with anything else.
+	ctx.Log().WithField("pod", podName).Info("suspending pod")
+	if ctx.ExpectingResponse() {
+		ctx.Respond(PodSuspended{PodID: msg.PodID})
+	}
+
+	// The pod handler marks itself suspended before deleting its resources, so the
+	// expected exit is not reported to the task actor as a container failure.
+	p.terminatingPods[podName] = true
+	ctx.Tell(ref, msg)
+}
+
+func (p *pods) receiveResumeTaskPod(ctx *actor.Context, msg ResumeTaskPod) error {
+	suspended, ok := p.suspendedPods[msg.PodID.String()]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received resume command for container that is not suspended")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no suspended pod found for container %s", msg.PodID))
+		}
+		return nil
+	}
+	// The old handler lives until the suspended pod finishes terminating; recreating
+	// the pod while it is still registered would collide with it, so the restart is
+	// deferred until the handler stops.
+	if podName, tracked := p.containerIDToPodName[msg.PodID.String()]; tracked {
+		if _, alive := p.podNameToPodHandler[podName]; alive {
+			ctx.Log().WithField("pod-id", msg.PodID).Info(
+				"deferring resume until the suspended pod finishes terminating")
+			suspended.resumeOnStop = true
+			p.suspendedPods[msg.PodID.String()] = suspended
+			return nil
+		}
+	}
+
+	return p.resumeSuspendedPod(ctx, msg.PodID.String(), suspended)
+}
+
+// resumeSuspendedPod recreates a suspended pod from its checkpoint. The checkpoint is
+// only dropped once the pod started successfully, so a failed resume can be retried.
+func (p *pods) resumeSuspendedPod(
+	ctx *actor.Context, containerID string, suspended suspendedPod,
+) error {
+	ctx.Log().WithField("pod-id", containerID).Info("resuming suspended pod")
+	if err := p.receiveStartTaskPod(ctx, suspended.start); err != nil {
+		return err
+	}
+	delete(p.suspendedPods, containerID)
+
+	// Restore the original queue position once the recreated pod registers; the patch is
+	// best effort and logs on failure if the pod has not appeared in the cluster yet.
+	if suspended.qPosition > 0 {
+		ctx.Tell(ctx.Self(), SetPodOrder{
+			PodID: cproto.ID(containerID), QPosition: suspended.qPosition,
+		})
+	}
+	return nil
+}
+
+func (p *pods) receivePodReschedule(ctx *actor.Context, msg RescheduleTaskPod) {
+	podName, ok := p.containerIDToPodName[msg.PodID.String()]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received reschedule command for unregistered container id")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod found for container %s", msg.PodID))
+		}
+		return
+	}
+	ref, ok := p.podNameToPodHandler[podName]
+	if !ok {
+		ctx.Log().WithField("pod-id", msg.PodID).Debug(
+			"received reschedule command for unregistered pod")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("no pod handler found for pod %s", podName))
+		}
+		return
+	}
+
+	if p.containerIDToSchedulingState[msg.PodID.String()] == job.SchedulingStateScheduled {
+		if ctx.ExpectingResponse() {
+			ctx.Respond(errors.Errorf("cannot reschedule pod %s: pod is already running", podName))
 		}
+		return
+	}
+
+	ctx.Tell(ref, msg)
+}
+
+func (p *pods) receivePodRescheduled(ctx *actor.Context, msg podRescheduled) {
+	ref, ok := p.podNameToPodHandler[msg.oldName]
+	if !ok {
+		ctx.Log().WithField("pod-name", msg.oldName).Warn(
+			"received pod rescheduled notification for un-registered pod")
+		return
+	}
+
+	containerID := p.podNameToContainerID[msg.oldName]
+	taskActor := p.podHandlerToMetadata[ref].taskActor
+	delete(p.podNameToPodHandler, msg.oldName)
+	delete(p.podNameToContainerID, msg.oldName)
+	p.podNameToPodHandler[msg.newName] = ref
+	p.podNameToContainerID[msg.newName] = containerID
+	p.containerIDToPodName[containerID] = msg.newName
+	p.podHandlerToMetadata[ref] = podMetadata{
+		podName: msg.newName, containerID: containerID, taskActor: taskActor,
+	}
+
+	// The rescheduled pod was freshly resubmitted, so its startup phases start over.
+	delete(p.podSchedulingTimes, msg.oldName)
+	p.podSchedulingTimes[msg.newName] = &podSchedulingTimes{submitted: time.Now().UTC()}
+
+	if start, ok := p.startMessages[msg.oldName]; ok {
+		delete(p.startMessages, msg.oldName)
+		p.startMessages[msg.newName] = start
+	}
+
+	if taskPod, ok := p.podNameToTaskPod[msg.oldName]; ok {
+		p.forgetTaskPod(ctx, msg.oldName)
+		taskPod.PodName = msg.newName
+		p.persistTaskPod(ctx, taskPod)
+	}
+}
+
+func (p *pods) cleanUpPodHandler(ctx *actor.Context, podHandler *actor.Ref) error {
+	podInfo, ok := p.podHandlerToMetadata[podHandler]
+	if !ok {
+		return errors.Errorf("unknown pod handler being deleted %s", podHandler.Address())
 	}
 
 	ctx.Log().WithField("pod", podInfo.podName).WithField(
 		"handler", podHandler.Address()).Infof("de-registering pod handler")
+	p.forgetTaskPod(ctx, podInfo.podName)
 	delete(p.podNameToPodHandler, podInfo.podName)
+	delete(p.terminatingPods, podInfo.podName)
 	delete(p.podNameToContainerID, podInfo.podName)
+	delete(p.podStatsCache, podInfo.podName)
+	delete(p.podStatuses, podInfo.podName)
+	delete(p.podSchedulingTimes, podInfo.podName)
+	delete(p.startMessages, podInfo.podName)
+	delete(p.containerIDToQPosition, podInfo.containerID)
+	delete(p.preemptionDeadlines, podInfo.podName)
 	delete(p.containerIDToPodName, podInfo.containerID)
 	delete(p.containerIDToSchedulingState, podInfo.containerID)
 	delete(p.podHandlerToMetadata, podHandler)
 
+	if p.terminatingTasks[podInfo.taskActor] {
+		remaining := false
+		for _, metadata := range p.podHandlerToMetadata {
+			if metadata.taskActor == podInfo.taskActor {
+				remaining = true
+				break
+			}
+		}
+		if !remaining {
+			delete(p.terminatingTasks, podInfo.taskActor)
+		}
+	}
+
+	// A resume that arrived while this handler was still tearing down was deferred
+	// until now; the handler is fully de-registered, so the pod can be recreated.
+	if suspended, ok := p.suspendedPods[podInfo.containerID]; ok && suspended.resumeOnStop {
+		suspended.resumeOnStop = false
+		p.suspendedPods[podInfo.containerID] = suspended
+		return p.resumeSuspendedPod(ctx, podInfo.containerID, suspended)
+	}
+
 	return nil
 }
 
@@ -563,6 +2553,23 @@ func (p *pods) handleGetAgentsRequest(ctx *actor.Context) {
 	ctx.Respond(response)
 }
 
+// nodeSlotCount returns how many slots a node provides under the configured slot type.
+func (p *pods) nodeSlotCount(node *k8sV1.Node) int64 {
+	switch p.slotType {
+	case device.CPU:
+		resources := node.Status.Allocatable["cpu"]
+		milliCPUs := resources.MilliValue() - p.nodeToSystemResourceRequests[node.Name]
+		return int64(float32(milliCPUs) / (1000. * p.slotResourceRequests.CPU))
+	case device.ROCM:
+		panic("ROCm is not supported on k8s yet")
+	case device.CUDA:
+		fallthrough
+	default:
+		resources := node.Status.Allocatable["nvidia.com/gpu"]
+		return resources.Value()
+	}
+}
+
 // summarize will return all nodes currently in the k8 cluster that have GPUs as agents.
 // It will map currently running Determined pods to the slots on these Nodes, marking all other
 // slots as Free, even if they are being used by other k8 pods.
@@ -587,22 +2594,10 @@ func (p *pods) summarize(ctx *actor.Context) map[string]model.AgentSummary {
 
 	summary := make(map[string]model.AgentSummary)
 	for _, node := range p.currentNodes {
-		var numSlots int64
-		var deviceType device.Type
-		switch p.slotType {
-		case device.CPU:
-			resources := node.Status.Allocatable["cpu"]
-			milliCPUs := resources.MilliValue() - p.nodeToSystemResourceRequests[node.Name]
-			numSlots = int64(float32(milliCPUs) / (1000. * p.slotResourceRequests.CPU))
+		numSlots := p.nodeSlotCount(node)
+		deviceType := device.CUDA
+		if p.slotType == device.CPU {
 			deviceType = device.CPU
-		case device.ROCM:
-			panic("ROCm is not supported on k8s yet")
-		case device.CUDA:
-			fallthrough
-		default:
-			resources := node.Status.Allocatable["nvidia.com/gpu"]
-			numSlots = resources.Value()
-			deviceType = device.CUDA
 		}
 		if numSlots < 1 {
 			continue
@@ -0,0 +1,255 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+)
+
+// podsUnderTest runs a pods struct as an actor while skipping the real PreStart, which
+// would try to connect to a kubernetes cluster.
+type podsUnderTest struct {
+	*pods
+}
+
+func (p podsUnderTest) Receive(ctx *actor.Context) error {
+	if _, ok := ctx.Message().(actor.PreStart); ok {
+		return nil
+	}
+	return p.pods.Receive(ctx)
+}
+
+// createPodsWithTerminatingPod builds a pods actor tracking two pods backed by mock
+// receivers: "terminating-pod" already observed with a deletion in progress and
+// "active-pod" running normally.
+func createPodsWithTerminatingPod(t *testing.T) (
+	*actor.System, *actor.Ref, *mockReceiver, *mockReceiver,
+) {
+	system := actor.NewSystem("test-sys")
+
+	terminatingHandler := newMockReceiver("terminating-pod-handler")
+	terminatingRef, ok := system.ActorOf(actor.Addr("terminating-pod"), terminatingHandler)
+	assert.Assert(t, ok)
+
+	activeHandler := newMockReceiver("active-pod-handler")
+	activeRef, ok := system.ActorOf(actor.Addr("active-pod"), activeHandler)
+	assert.Assert(t, ok)
+
+	p := &pods{
+		podNameToPodHandler: map[string]*actor.Ref{
+			"terminating-pod": terminatingRef,
+			"active-pod":      activeRef,
+		},
+		containerIDToPodName: map[string]string{
+			"terminating-container": "terminating-pod",
+			"active-container":      "active-pod",
+		},
+		containerIDToQPosition: make(map[string]float64),
+		terminatingPods:        map[string]bool{"terminating-pod": true},
+	}
+	podsRef, ok := system.ActorOf(actor.Addr("pods"), podsUnderTest{p})
+	assert.Assert(t, ok)
+
+	// Let the actors finish starting before clearing their PreStart messages.
+	time.Sleep(time.Millisecond * 100)
+	terminatingHandler.Purge()
+	activeHandler.Purge()
+	return system, podsRef, terminatingHandler, activeHandler
+}
+
+func TestChangePriorityIgnoresTerminatingPod(t *testing.T) {
+	system, podsRef, terminatingHandler, activeHandler := createPodsWithTerminatingPod(t)
+
+	system.Tell(podsRef, ChangePriority{PodID: "terminating-container"})
+	system.Tell(podsRef, ChangePriority{PodID: "active-container"})
+	time.Sleep(time.Millisecond * 500)
+
+	assert.Equal(t, terminatingHandler.GetLength(), 0)
+	assert.Equal(t, activeHandler.GetLength(), 1)
+}
+
+func TestSetPodOrderIgnoresTerminatingPod(t *testing.T) {
+	system, podsRef, terminatingHandler, activeHandler := createPodsWithTerminatingPod(t)
+
+	// The handler must bail out before patching the pod: the fixture has no client set,
+	// so reaching the patch would crash the actor and drop the follow-up message.
+	system.Tell(podsRef, SetPodOrder{PodID: "terminating-container", QPosition: 1})
+	system.Tell(podsRef, ChangePriority{PodID: "active-container"})
+	time.Sleep(time.Millisecond * 500)
+
+	assert.Equal(t, terminatingHandler.GetLength(), 0)
+	assert.Equal(t, activeHandler.GetLength(), 1)
+}
+
+func TestPreemptTaskPodIgnoresTerminatingPod(t *testing.T) {
+	system, podsRef, terminatingHandler, activeHandler := createPodsWithTerminatingPod(t)
+
+	system.Tell(podsRef, PreemptTaskPod{PodName: "terminating-pod"})
+	system.Tell(podsRef, PreemptTaskPod{PodName: "active-pod"})
+	time.Sleep(time.Millisecond * 500)
+
+	assert.Equal(t, terminatingHandler.GetLength(), 0)
+	assert.Equal(t, activeHandler.GetLength(), 1)
+}
+
+func TestValidateTaskSpec(t *testing.T) {
+	setupEntrypoint(t)
+	defer cleanup(t)
+
+	system := actor.NewSystem("test-sys")
+	p := &pods{
+		namespace:            "default",
+		clientSet:            &k8sClient.Clientset{},
+		masterIP:             "0.0.0.0",
+		masterPort:           32,
+		loggingConfig:        model.LoggingConfig{DefaultLoggingConfig: &model.DefaultLoggingConfig{}},
+		slotType:             device.CUDA,
+		slotResourceRequests: PodSlotResourceRequests{},
+		scheduler:            "default-scheduler",
+		gpuTypeNodeLabel:     DefaultGPUTypeNodeLabel,
+		fluentConfig:         DefaultFluentConfig,
+		podInterface:         &mockPodInterface{pods: make(map[string]*k8sV1.Pod)},
+	}
+	podsRef, ok := system.ActorOf(actor.Addr("pods"), podsUnderTest{p})
+	assert.Assert(t, ok)
+
+	commandSpec := tasks.GenericCommandSpec{
+		Base: tasks.TaskSpec{
+			AllocationID:     "task",
+			ContainerID:      "container",
+			ClusterID:        "cluster",
+			AgentUserGroup:   createAgentUserGroup(),
+			Owner:            createUser(),
+			UserSessionToken: "bogus",
+		},
+		Config: model.CommandConfig{Description: "test-config"},
+	}
+
+	// No TaskActor is set on purpose: validation must not require one.
+	response := system.Ask(podsRef, ValidateTaskSpec{Spec: commandSpec.ToTaskSpec(nil), Slots: 1})
+	validation, ok := response.Get().(TaskSpecValidation)
+	assert.Assert(t, ok)
+	assert.Assert(t, validation.Valid)
+}
+
+// createPodsWithStatuses builds a pods actor tracking numPods pods with cached compact
+// statuses, as left behind by watch status updates.
+func createPodsWithStatuses(numPods int) (*actor.System, *actor.Ref, []cproto.ID) {
+	containerIDToPodName := make(map[string]string, numPods)
+	podStatuses := make(map[string]trackedPodStatus, numPods)
+	podIDs := make([]cproto.ID, 0, numPods)
+	for i := 0; i < numPods; i++ {
+		podName := fmt.Sprintf("determined-pod-%d", i)
+		containerID := fmt.Sprintf("container-%d", i)
+		containerIDToPodName[containerID] = podName
+		podStatuses[podName] = trackedPodStatus{
+			status:    PodStatus{PodName: podName, Phase: "Running", NodeName: "node-a"},
+			createdAt: time.Now(),
+		}
+		podIDs = append(podIDs, cproto.ID(containerID))
+	}
+
+	system := actor.NewSystem("bench-sys")
+	podsRef, _ := system.ActorOf(actor.Addr("pods"), podsUnderTest{&pods{
+		containerIDToPodName: containerIDToPodName,
+		podStatuses:          podStatuses,
+	}})
+	return system, podsRef, podIDs
+}
+
+// BenchmarkGetPodStatusesPerPod measures a dashboard refreshing pod states with one
+// request per pod, the round-trip pattern GetPodStatuses replaces.
+func BenchmarkGetPodStatusesPerPod(b *testing.B) {
+	system, podsRef, podIDs := createPodsWithStatuses(500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, podID := range podIDs {
+			response := system.Ask(podsRef, GetPodStatuses{PodIDs: []cproto.ID{podID}})
+			if statuses := response.Get().(map[cproto.ID]PodStatus); len(statuses) != 1 {
+				b.Fatalf("expected one status, got %d", len(statuses))
+			}
+		}
+	}
+}
+
+// BenchmarkGetPodStatusesBulk measures the same refresh issued as a single bulk request.
+func BenchmarkGetPodStatusesBulk(b *testing.B) {
+	system, podsRef, podIDs := createPodsWithStatuses(500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		response := system.Ask(podsRef, GetPodStatuses{})
+		if statuses := response.Get().(map[cproto.ID]PodStatus); len(statuses) != len(podIDs) {
+			b.Fatalf("expected %d statuses, got %d", len(podIDs), len(statuses))
+		}
+	}
+}
+
+func clusterOfPods(numPods int) map[string]*k8sV1.Pod {
+	pods := make(map[string]*k8sV1.Pod, numPods)
+	for i := 0; i < numPods; i++ {
+		name := fmt.Sprintf("determined-pod-%d", i)
+		pods[name] = &k8sV1.Pod{ObjectMeta: metaV1.ObjectMeta{
+			Name: name, Namespace: "default",
+		}}
+	}
+	return pods
+}
+
+// BenchmarkReconcileFromList measures reconciliation that issues a full LIST of cluster
+// pods on every pass, the behavior the watch-derived cache replaces.
+func BenchmarkReconcileFromList(b *testing.B) {
+	podInterface := &mockPodInterface{pods: clusterOfPods(1000)}
+	tracked := make(map[string]string, len(podInterface.pods))
+	for name := range podInterface.pods {
+		tracked[name] = "container-id"
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		clusterPods, err := podInterface.List(context.TODO(), metaV1.ListOptions{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		inCluster := make(map[string]bool, len(clusterPods.Items))
+		for _, pod := range clusterPods.Items {
+			inCluster[pod.Name] = true
+		}
+		reconcilePodStates(tracked, inCluster)
+	}
+	b.ReportMetric(float64(podInterface.listCount)/float64(b.N), "lists/op")
+}
+
+// BenchmarkReconcileFromWatchCache measures reconciliation served from the watch-derived
+// cache of cluster pods, which issues no LIST calls in steady state.
+func BenchmarkReconcileFromWatchCache(b *testing.B) {
+	podInterface := &mockPodInterface{pods: clusterOfPods(1000)}
+	clusterPods := make(map[string]string, len(podInterface.pods))
+	tracked := make(map[string]string, len(podInterface.pods))
+	for name, pod := range podInterface.pods {
+		clusterPods[name] = pod.Namespace
+		tracked[name] = "container-id"
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		inCluster := make(map[string]bool, len(clusterPods))
+		for name := range clusterPods {
+			inCluster[name] = true
+		}
+		reconcilePodStates(tracked, inCluster)
+	}
+	b.ReportMetric(float64(podInterface.listCount)/float64(b.N), "lists/op")
+}
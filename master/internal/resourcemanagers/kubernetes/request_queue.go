@@ -6,8 +6,11 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/logger"
 
+	batchV1 "k8s.io/api/batch/v1"
 	k8sV1 "k8s.io/api/core/v1"
+	typedBatchV1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
@@ -22,12 +25,33 @@ type (
 		handler       *actor.Ref
 		podSpec       *k8sV1.Pod
 		configMapSpec *k8sV1.ConfigMap
+		// jobSpec, when non-nil, is created instead of podSpec; the job controller then
+		// creates the pods from the template embedded in it.
+		jobSpec    *batchV1.Job
+		logContext logger.Context
 	}
 
 	deleteKubernetesResources struct {
 		handler       *actor.Ref
 		podName       string
 		configMapName string
+		// jobName, when non-empty, is deleted with foreground propagation, which
+		// cascades to the pods the job created.
+		jobName string
+		// namespace is the namespace the resources live in. When empty, the resource
+		// manager's default namespace is used.
+		namespace string
+		// gracePeriodSeconds overrides the default deletion grace period when non-nil.
+		gracePeriodSeconds *int64
+	}
+
+	// registerNamespace is sent by the pods actor when it starts managing a new
+	// namespace, so that workers issue requests against the right interfaces.
+	registerNamespace struct {
+		namespace          string
+		podInterface       typedV1.PodInterface
+		configMapInterface typedV1.ConfigMapInterface
+		jobInterface       typedBatchV1.JobInterface
 	}
 )
 
@@ -64,42 +88,45 @@ type queuedResourceRequest struct {
 // There are two reasons a queue system is required as opposed to allowing the pod actors
 // to create and delete Kubernetes resources asynchronously themselves:
 //
-//    1) Each pod creation first requires the creation of a configMap, however creating the two
-//       is not an atomic operation. If there is a large number of concurrent creation requests
-//       (e.g., a large HP search experiment) the kubernetes API server ends up processing the
-//       creation of all the configMaps before starting to create pods, which adds significant
-//       latency to the creation of pods.
+//  1. Each pod creation first requires the creation of a configMap, however creating the two
+//     is not an atomic operation. If there is a large number of concurrent creation requests
+//     (e.g., a large HP search experiment) the kubernetes API server ends up processing the
+//     creation of all the configMaps before starting to create pods, which adds significant
+//     latency to the creation of pods.
 //
-//    2) If all creation and deletion requests are submitted asynchronously, it is possible the
-//       Kubernetes API server will temporarily become saturated, and be slower to respond to other
-//       requests.
+//  2. If all creation and deletion requests are submitted asynchronously, it is possible the
+//     Kubernetes API server will temporarily become saturated, and be slower to respond to other
+//     requests.
 //
-//  When requests come in they are buffered by the requestQueue until a worker becomes available
-//  at which point the longest queue request is forwarded to the available. Requests are buffered
-//  rather than forward right away because buffering makes it possible to cancel creation requests
-//  after they are created, but before they are executed. Since the actor system processes messages
-//  in a FIFO order, if all request were forwarded right away any cancellation request would only
-//  be processed after the creation request case already been processed, requiring an unnecessary
-//  resource creation and deletion. An example of this is when a large HP search is created and
-//  then killed moments later. By having requests be buffered, if a deletion request arrives
-//  prior to the creation request being executed, the requestQueue detects this and skips the
-//  unnecessary creation / deletion.
+//     When requests come in they are buffered by the requestQueue until a worker becomes available
+//     at which point the longest queue request is forwarded to the available. Requests are buffered
+//     rather than forward right away because buffering makes it possible to cancel creation requests
+//     after they are created, but before they are executed. Since the actor system processes messages
+//     in a FIFO order, if all request were forwarded right away any cancellation request would only
+//     be processed after the creation request case already been processed, requiring an unnecessary
+//     resource creation and deletion. An example of this is when a large HP search is created and
+//     then killed moments later. By having requests be buffered, if a deletion request arrives
+//     prior to the creation request being executed, the requestQueue detects this and skips the
+//     unnecessary creation / deletion.
 //
-//  The message protocol consists of `createKubernetesResources` and `deleteKubernetesResources`
-//  messages being sent to the requestQueue. If it forwards the request to a worker, the worker
-//  will send the original task handler a `resourceCreationFailed` or a `resourceDeletionFailed`
-//  if an error was encountered while creating / deleting the resources. If a deletion request
-//  arrives before the creation request had been sent to the worker, the `requestQueue` will
-//  notify the task handler of this by sending a `resourceCreationCancelled` message.
-//  requestProcessingWorkers notify the requestQueue that they are available to receive work
-//  by sending a `workerAvailable` message.
+//     The message protocol consists of `createKubernetesResources` and `deleteKubernetesResources`
+//     messages being sent to the requestQueue. If it forwards the request to a worker, the worker
+//     will send the original task handler a `resourceCreationFailed` or a `resourceDeletionFailed`
+//     if an error was encountered while creating / deleting the resources. If a deletion request
+//     arrives before the creation request had been sent to the worker, the `requestQueue` will
+//     notify the task handler of this by sending a `resourceCreationCancelled` message.
+//     requestProcessingWorkers notify the requestQueue that they are available to receive work
+//     by sending a `workerAvailable` message.
 type requestQueue struct {
 	podInterface       typedV1.PodInterface
 	configMapInterface typedV1.ConfigMapInterface
+	jobInterface       typedBatchV1.JobInterface
+	creationRetry      PodCreationRetryConfig
 
 	queue                    []*queuedResourceRequest
 	pendingResourceCreations map[*actor.Ref]*queuedResourceRequest
 	availableWorkers         []*actor.Ref
+	workers                  []*actor.Ref
 
 	creationInProgress       map[*actor.Ref]bool
 	blockedResourceDeletions map[*actor.Ref]*queuedResourceRequest
@@ -108,10 +135,14 @@ type requestQueue struct {
 func newRequestQueue(
 	podInterface typedV1.PodInterface,
 	configMapInterface typedV1.ConfigMapInterface,
+	jobInterface typedBatchV1.JobInterface,
+	creationRetry PodCreationRetryConfig,
 ) *requestQueue {
 	return &requestQueue{
 		podInterface:       podInterface,
 		configMapInterface: configMapInterface,
+		jobInterface:       jobInterface,
+		creationRetry:      creationRetry,
 
 		queue:                    make([]*queuedResourceRequest, 0),
 		pendingResourceCreations: make(map[*actor.Ref]*queuedResourceRequest),
@@ -129,13 +160,22 @@ func (r *requestQueue) Receive(ctx *actor.Context) error {
 			newWorker, ok := ctx.ActorOf(
 				fmt.Sprintf("kubernetes-worker-%d", i),
 				&requestProcessingWorker{
-					podInterface:       r.podInterface,
-					configMapInterface: r.configMapInterface,
+					podInterfaces: map[string]typedV1.PodInterface{
+						"": r.podInterface,
+					},
+					configMapInterfaces: map[string]typedV1.ConfigMapInterface{
+						"": r.configMapInterface,
+					},
+					jobInterfaces: map[string]typedBatchV1.JobInterface{
+						"": r.jobInterface,
+					},
+					creationRetry: r.creationRetry,
 				},
 			)
 			if !ok {
 				return errors.Errorf("%s actor already exists", newWorker.Address())
 			}
+			r.workers = append(r.workers, newWorker)
 		}
 	case actor.PostStop:
 		// This should not happen since the request queue actor would not stop during
@@ -147,6 +187,11 @@ func (r *requestQueue) Receive(ctx *actor.Context) error {
 	case deleteKubernetesResources:
 		r.receiveDeleteKubernetesResources(ctx, msg)
 
+	case registerNamespace:
+		for _, worker := range r.workers {
+			ctx.Tell(worker, msg)
+		}
+
 	case workerAvailable:
 		r.receiveWorkerAvailable(ctx, msg)
 
@@ -85,7 +85,7 @@ func TestRequestQueueCreatingManyPod(t *testing.T) {
 	podInterface := &mockPodInterface{pods: make(map[string]*k8sV1.Pod)}
 	configMapInterface := &mockConfigMapInterface{configMaps: make(map[string]*k8sV1.ConfigMap)}
 
-	k8sRequestQueue := newRequestQueue(podInterface, configMapInterface)
+	k8sRequestQueue := newRequestQueue(podInterface, configMapInterface, nil, DefaultPodCreationRetryConfig)
 	requestQueueActor, _ := system.ActorOf(
 		actor.Addr("request-queue"),
 		k8sRequestQueue,
@@ -113,7 +113,7 @@ func TestRequestQueueCreatingAndDeletingManyPod(t *testing.T) {
 	podInterface := &mockPodInterface{pods: make(map[string]*k8sV1.Pod)}
 	configMapInterface := &mockConfigMapInterface{configMaps: make(map[string]*k8sV1.ConfigMap)}
 
-	k8sRequestQueue := newRequestQueue(podInterface, configMapInterface)
+	k8sRequestQueue := newRequestQueue(podInterface, configMapInterface, nil, DefaultPodCreationRetryConfig)
 	requestQueueActor, _ := system.ActorOf(
 		actor.Addr("request-queue"),
 		k8sRequestQueue,
@@ -142,7 +142,7 @@ func TestRequestQueueCreatingThenDeletingManyPods(t *testing.T) {
 	podInterface := &mockPodInterface{pods: make(map[string]*k8sV1.Pod)}
 	configMapInterface := &mockConfigMapInterface{configMaps: make(map[string]*k8sV1.ConfigMap)}
 
-	k8sRequestQueue := newRequestQueue(podInterface, configMapInterface)
+	k8sRequestQueue := newRequestQueue(podInterface, configMapInterface, nil, DefaultPodCreationRetryConfig)
 	requestQueueActor, _ := system.ActorOf(
 		actor.Addr("request-queue"),
 		k8sRequestQueue,
@@ -179,7 +179,7 @@ func TestRequestQueueCreatingAndDeletingManyPodWithDelay(t *testing.T) {
 	}
 	configMapInterface := &mockConfigMapInterface{configMaps: make(map[string]*k8sV1.ConfigMap)}
 
-	k8sRequestQueue := newRequestQueue(podInterface, configMapInterface)
+	k8sRequestQueue := newRequestQueue(podInterface, configMapInterface, nil, DefaultPodCreationRetryConfig)
 	requestQueueActor, _ := system.ActorOf(
 		actor.Addr("request-queue"),
 		k8sRequestQueue,
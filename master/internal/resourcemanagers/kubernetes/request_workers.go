@@ -2,16 +2,52 @@ package kubernetes
 
 import (
 	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/master/pkg/actor"
 
+	batchV1 "k8s.io/api/batch/v1"
+	k8sV1 "k8s.io/api/core/v1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilNet "k8s.io/apimachinery/pkg/util/net"
+	typedBatchV1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	typedV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
+// requestProcessingWorker issues creation and deletion requests against the kubernetes
+// API. Its interfaces are keyed by namespace; the empty key holds the resource manager's
+// default namespace and is the fallback for namespaces that have not been registered.
 type requestProcessingWorker struct {
-	podInterface       typedV1.PodInterface
-	configMapInterface typedV1.ConfigMapInterface
+	podInterfaces       map[string]typedV1.PodInterface
+	configMapInterfaces map[string]typedV1.ConfigMapInterface
+	jobInterfaces       map[string]typedBatchV1.JobInterface
+	creationRetry       PodCreationRetryConfig
+}
+
+func (r *requestProcessingWorker) podInterfaceFor(namespace string) typedV1.PodInterface {
+	if podInterface, ok := r.podInterfaces[namespace]; ok {
+		return podInterface
+	}
+	return r.podInterfaces[""]
+}
+
+func (r *requestProcessingWorker) configMapInterfaceFor(
+	namespace string,
+) typedV1.ConfigMapInterface {
+	if configMapInterface, ok := r.configMapInterfaces[namespace]; ok {
+		return configMapInterface
+	}
+	return r.configMapInterfaces[""]
+}
+
+func (r *requestProcessingWorker) jobInterfaceFor(namespace string) typedBatchV1.JobInterface {
+	if jobInterface, ok := r.jobInterfaces[namespace]; ok {
+		return jobInterface
+	}
+	return r.jobInterfaces[""]
 }
 
 func (r *requestProcessingWorker) Receive(ctx *actor.Context) error {
@@ -30,6 +66,11 @@ func (r *requestProcessingWorker) Receive(ctx *actor.Context) error {
 		r.receiveDeleteKubernetesResources(ctx, msg)
 		ctx.Tell(ctx.Self().Parent(), workerAvailable{})
 
+	case registerNamespace:
+		r.podInterfaces[msg.namespace] = msg.podInterface
+		r.configMapInterfaces[msg.namespace] = msg.configMapInterface
+		r.jobInterfaces[msg.namespace] = msg.jobInterface
+
 	default:
 		ctx.Log().Errorf("unexpected message %T", msg)
 		return actor.ErrUnexpectedMessage(ctx)
@@ -41,8 +82,13 @@ func (r *requestProcessingWorker) receiveCreateKubernetesResources(
 	ctx *actor.Context,
 	msg createKubernetesResources,
 ) {
-	configMap, err := r.configMapInterface.Create(
-		context.TODO(), msg.configMapSpec, metaV1.CreateOptions{})
+	var configMap *k8sV1.ConfigMap
+	err := r.retryOnTransientError(ctx, msg, msg.configMapSpec.Name, func() error {
+		var createErr error
+		configMap, createErr = r.configMapInterfaceFor(msg.configMapSpec.Namespace).Create(
+			context.TODO(), msg.configMapSpec, metaV1.CreateOptions{})
+		return createErr
+	})
 	if err != nil {
 		ctx.Log().WithField("handler", msg.handler.Address()).WithError(err).Errorf(
 			"error creating configMap %s", msg.configMapSpec.Name)
@@ -52,8 +98,33 @@ func (r *requestProcessingWorker) receiveCreateKubernetesResources(
 	ctx.Log().WithField("handler", msg.handler.Address()).Infof(
 		"created configMap %s", configMap.Name)
 
+	if msg.jobSpec != nil {
+		ctx.Log().Debugf("launching job with spec %v", msg.jobSpec)
+		var job *batchV1.Job
+		err = r.retryOnTransientError(ctx, msg, msg.jobSpec.Name, func() error {
+			var createErr error
+			job, createErr = r.jobInterfaceFor(msg.jobSpec.Namespace).Create(
+				context.TODO(), msg.jobSpec, metaV1.CreateOptions{})
+			return createErr
+		})
+		if err != nil {
+			ctx.Log().WithField("handler", msg.handler.Address()).WithError(err).Errorf(
+				"error creating job %s", msg.jobSpec.Name)
+			ctx.Tell(msg.handler, resourceCreationFailed{err: err})
+			return
+		}
+		ctx.Log().WithField("handler", msg.handler.Address()).Infof("created job %s", job.Name)
+		return
+	}
+
 	ctx.Log().Debugf("launching pod with spec %v", msg.podSpec)
-	pod, err := r.podInterface.Create(context.TODO(), msg.podSpec, metaV1.CreateOptions{})
+	var pod *k8sV1.Pod
+	err = r.retryOnTransientError(ctx, msg, msg.podSpec.Name, func() error {
+		var createErr error
+		pod, createErr = r.podInterfaceFor(msg.podSpec.Namespace).Create(
+			context.TODO(), msg.podSpec, metaV1.CreateOptions{})
+		return createErr
+	})
 	if err != nil {
 		ctx.Log().WithField("handler", msg.handler.Address()).WithError(err).Errorf(
 			"error creating pod %s", msg.podSpec.Name)
@@ -63,17 +134,81 @@ func (r *requestProcessingWorker) receiveCreateKubernetesResources(
 	ctx.Log().WithField("handler", msg.handler.Address()).Infof("created pod %s", pod.Name)
 }
 
+// retryOnTransientError runs the provided resource creation attempt, retrying with
+// exponential backoff as long as it fails with a transient API error. Permanent errors
+// (e.g., an invalid spec or exceeded quota) are returned right away.
+func (r *requestProcessingWorker) retryOnTransientError(
+	ctx *actor.Context,
+	msg createKubernetesResources,
+	resourceName string,
+	attempt func() error,
+) error {
+	backoff := time.Second
+	maxBackoff := time.Duration(r.creationRetry.MaxBackoffSeconds) * time.Second
+
+	for numRetries := 0; ; numRetries++ {
+		err := attempt()
+		if err == nil || !isTransientAPIError(err) || numRetries >= r.creationRetry.MaxRetries {
+			return err
+		}
+
+		ctx.Log().WithFields(log.Fields(msg.logContext)).WithError(err).Warnf(
+			"transient error creating %s, retrying in %s (%d/%d)",
+			resourceName, backoff, numRetries+1, r.creationRetry.MaxRetries)
+		time.Sleep(backoff)
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// isTransientAPIError reports whether a kubernetes API error is expected to resolve on
+// its own shortly (HTTP 429, 500, 503, or a refused connection).
+func isTransientAPIError(err error) bool {
+	switch {
+	case k8error.IsTooManyRequests(err),
+		k8error.IsInternalError(err),
+		k8error.IsServiceUnavailable(err),
+		k8error.IsServerTimeout(err),
+		k8error.IsTimeout(err):
+		return true
+	}
+	return utilNet.IsConnectionRefused(err)
+}
+
 func (r *requestProcessingWorker) receiveDeleteKubernetesResources(
 	ctx *actor.Context,
 	msg deleteKubernetesResources,
 ) {
 	var gracePeriod int64 = deletionGracePeriod
+	if msg.gracePeriodSeconds != nil {
+		gracePeriod = *msg.gracePeriodSeconds
+	}
 	var err error
 
+	// Deleting a job with foreground propagation cascades to the pods the job
+	// controller created for it, so job-mode tasks need no separate pod deletion.
+	if len(msg.jobName) > 0 {
+		propagation := metaV1.DeletePropagationForeground
+		err = r.jobInterfaceFor(msg.namespace).Delete(
+			context.TODO(), msg.jobName, metaV1.DeleteOptions{
+				GracePeriodSeconds: &gracePeriod,
+				PropagationPolicy:  &propagation,
+			})
+		if err != nil {
+			ctx.Log().WithField("handler", msg.handler.Address()).WithError(err).Errorf(
+				"failed to delete job %s", msg.jobName)
+		} else {
+			ctx.Log().WithField("handler", msg.handler.Address()).Infof(
+				"deleted job %s", msg.jobName)
+		}
+	}
+
 	// If resource creation failed, we will still try to delete those resources which
 	// will also result in a failure.
 	if len(msg.podName) > 0 {
-		err = r.podInterface.Delete(
+		err = r.podInterfaceFor(msg.namespace).Delete(
 			context.TODO(), msg.podName, metaV1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
 		if err != nil {
 			ctx.Log().WithField("handler", msg.handler.Address()).WithError(err).Errorf(
@@ -85,7 +220,7 @@ func (r *requestProcessingWorker) receiveDeleteKubernetesResources(
 	}
 
 	if len(msg.configMapName) > 0 {
-		errDeletingConfigMap := r.configMapInterface.Delete(
+		errDeletingConfigMap := r.configMapInterfaceFor(msg.namespace).Delete(
 			context.TODO(), msg.configMapName,
 			metaV1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
 		if errDeletingConfigMap != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -25,8 +26,10 @@ import (
 
 	k8sV1 "k8s.io/api/core/v1"
 	schedulingV1 "k8s.io/api/scheduling/v1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -38,13 +41,18 @@ const (
 	cmdTask             = "cmd"
 
 	rootUserName = "root"
+
+	// checkpointFetchContainerName is the fixed name of the init container that fetches
+	// the task's checkpoint, so its failure can be told apart from a training crash.
+	checkpointFetchContainerName = "determined-checkpoint-fetch"
 )
 
 func (p *pod) configureResourcesRequirements() k8sV1.ResourceRequirements {
+	var requirements k8sV1.ResourceRequirements
 	switch p.slotType {
 	case device.CPU:
 		cpuMillisRequested := int64(p.slotResourceRequests.CPU * float32(p.slots) * 1000)
-		return k8sV1.ResourceRequirements{
+		requirements = k8sV1.ResourceRequirements{
 			Limits: map[k8sV1.ResourceName]resource.Quantity{
 				"cpu": *resource.NewMilliQuantity(cpuMillisRequested, resource.DecimalSI),
 			},
@@ -57,15 +65,40 @@ func (p *pod) configureResourcesRequirements() k8sV1.ResourceRequirements {
 	case device.CUDA: // default to CUDA-backed slots.
 		fallthrough
 	default:
-		return k8sV1.ResourceRequirements{
+		// A task-specified slot resource name, e.g., a time-sliced GPU exposed by a
+		// device plugin, replaces the whole-GPU resource.
+		slotResource := k8sV1.ResourceName("nvidia.com/gpu")
+		if p.taskSpec.SlotResourceName != "" {
+			slotResource = k8sV1.ResourceName(p.taskSpec.SlotResourceName)
+		}
+		requirements = k8sV1.ResourceRequirements{
 			Limits: map[k8sV1.ResourceName]resource.Quantity{
-				"nvidia.com/gpu": *resource.NewQuantity(int64(p.slots), resource.DecimalSI),
+				slotResource: *resource.NewQuantity(int64(p.slots), resource.DecimalSI),
 			},
 			Requests: map[k8sV1.ResourceName]resource.Quantity{
-				"nvidia.com/gpu": *resource.NewQuantity(int64(p.slots), resource.DecimalSI),
+				slotResource: *resource.NewQuantity(int64(p.slots), resource.DecimalSI),
 			},
 		}
 	}
+
+	// Reserving ephemeral storage both requests and limits it, so tasks with large local
+	// scratch needs are placed on nodes with room rather than evicted under DiskPressure.
+	if !p.taskSpec.EphemeralStorage.IsZero() {
+		requirements.Limits["ephemeral-storage"] = p.taskSpec.EphemeralStorage
+		requirements.Requests["ephemeral-storage"] = p.taskSpec.EphemeralStorage
+	}
+
+	// Task-level overrides are applied last, so operators shape the QoS class directly:
+	// equal requests and limits remain Guaranteed, a limit above its request makes the
+	// container Burstable.
+	for name, quantity := range p.taskSpec.ResourceRequests {
+		requirements.Requests[k8sV1.ResourceName(name)] = quantity
+	}
+	for name, quantity := range p.taskSpec.ResourceLimits {
+		requirements.Limits[k8sV1.ResourceName(name)] = quantity
+	}
+
+	return requirements
 }
 
 func (p *pod) configureEnvVars(
@@ -172,6 +205,47 @@ func (p *pod) configureLoggingVolumes(
 	return mounts, volumes
 }
 
+// configurePVCVolumes converts the PersistentVolumeClaim mounts requested in the task
+// spec into volumes and mounts for the task container. Mount paths that collide with the
+// mounts Determined itself configures are rejected, since silently shadowing them would
+// break the task in hard-to-diagnose ways.
+func (p *pod) configurePVCVolumes(
+	reservedMounts []k8sV1.VolumeMount,
+) ([]k8sV1.VolumeMount, []k8sV1.Volume, error) {
+	reservedPaths := make(map[string]bool, len(reservedMounts))
+	for _, mount := range reservedMounts {
+		reservedPaths[mount.MountPath] = true
+	}
+
+	var mounts []k8sV1.VolumeMount
+	var volumes []k8sV1.Volume
+	for i, pvc := range p.taskSpec.Volumes {
+		if reservedPaths[pvc.MountPath] {
+			return nil, nil, errors.Errorf(
+				"mount path %s of claim %s collides with a mount reserved by determined",
+				pvc.MountPath, pvc.ClaimName)
+		}
+		reservedPaths[pvc.MountPath] = true
+
+		volumeName := fmt.Sprintf("determined-pvc-%d", i)
+		volumes = append(volumes, k8sV1.Volume{
+			Name: volumeName,
+			VolumeSource: k8sV1.VolumeSource{
+				PersistentVolumeClaim: &k8sV1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvc.ClaimName,
+					ReadOnly:  pvc.ReadOnly,
+				},
+			},
+		})
+		mounts = append(mounts, k8sV1.VolumeMount{
+			Name:      volumeName,
+			MountPath: pvc.MountPath,
+			ReadOnly:  pvc.ReadOnly,
+		})
+	}
+	return mounts, volumes, nil
+}
+
 func (p *pod) configureVolumes(
 	ctx *actor.Context,
 	dockerMounts []mount.Mount,
@@ -220,13 +294,22 @@ func (p *pod) modifyPodSpec(newPod *k8sV1.Pod, scheduler string) {
 			newPod.Spec.SchedulerName = scheduler
 		}
 		p.configureCoscheduler(newPod, scheduler)
+	} else if p.taskSpec.SchedulerName != "" && newPod.Spec.SchedulerName == "" {
+		newPod.Spec.SchedulerName = p.taskSpec.SchedulerName
 	}
 
-	if newPod.Spec.PriorityClassName == "" && p.taskSpec.ResourcesConfig.Priority() != nil {
-		priority := int32(*p.taskSpec.ResourcesConfig.Priority())
-		name := fmt.Sprintf("%s-priorityclass", p.taskSpec.ContainerID)
+	var priority *int32
+	if p.priority != nil {
+		priority = p.priority
+	} else if taskPriority := p.taskSpec.ResourcesConfig.Priority(); taskPriority != nil {
+		taskPriority := int32(*taskPriority)
+		priority = &taskPriority
+	}
+
+	if newPod.Spec.PriorityClassName == "" && priority != nil {
+		name := priorityClassName(*priority)
 
-		err := p.createPriorityClass(name, priority)
+		err := p.ensurePriorityClass(name, *priority)
 
 		if err == nil {
 			newPod.Spec.PriorityClassName = name
@@ -236,6 +319,13 @@ func (p *pod) modifyPodSpec(newPod *k8sV1.Pod, scheduler string) {
 	}
 }
 
+// priorityClassName returns the name of the priority class shared by all pods running at
+// the given priority. The classes are shared rather than per-container so non-Determined
+// workloads can reference the same classes and be preempted by the kube-scheduler itself.
+func priorityClassName(priority int32) string {
+	return fmt.Sprintf("determined-priority-%d", priority)
+}
+
 func (p *pod) configureCoscheduler(newPod *k8sV1.Pod, scheduler string) {
 	if newPod.Spec.SchedulerName != scheduler {
 		return
@@ -266,10 +356,62 @@ func (p *pod) configureCoscheduler(newPod *k8sV1.Pod, scheduler string) {
 	}
 }
 
-func (p *pod) createPriorityClass(name string, priority int32) error {
-	preemptionPolicy := k8sV1.PreemptNever
+// applyExtraPodMetadata merges the task-provided labels and annotations into the pod
+// metadata. Labels Determined relies on to find and reconcile pods are reserved; extra
+// labels colliding with them are dropped so that reconciliation is not corrupted.
+func (p *pod) applyExtraPodMetadata(ctx *actor.Context, podSpec *k8sV1.Pod) {
+	reservedLabels := map[string]bool{
+		determinedLabel:              true,
+		determinedPreemptionLabel:    true,
+		determinedSystemLabel:        true,
+		determinedNodePoolLabel:      true,
+		determinedQueuePositionLabel: true,
+	}
+
+	for key, value := range p.taskSpec.ExtraPodLabels {
+		if reservedLabels[key] {
+			ctx.Log().Warnf("dropping extra pod label %s: the label is reserved by determined", key)
+			continue
+		}
+		podSpec.ObjectMeta.Labels[key] = value
+	}
+
+	if len(p.taskSpec.ExtraPodAnnotations) > 0 && podSpec.ObjectMeta.Annotations == nil {
+		podSpec.ObjectMeta.Annotations = make(map[string]string)
+	}
+	for key, value := range p.taskSpec.ExtraPodAnnotations {
+		podSpec.ObjectMeta.Annotations[key] = value
+	}
+}
+
+func (p *pod) configurePoolScheduling(newPod *k8sV1.Pod, pool string) {
+	if newPod.Spec.NodeSelector == nil {
+		newPod.Spec.NodeSelector = make(map[string]string)
+	}
+	newPod.Spec.NodeSelector[determinedNodePoolLabel] = pool
+	newPod.Spec.Tolerations = append(newPod.Spec.Tolerations, k8sV1.Toleration{
+		Key:      determinedNodePoolLabel,
+		Operator: k8sV1.TolerationOpEqual,
+		Value:    pool,
+		Effect:   k8sV1.TaintEffectNoSchedule,
+	})
+}
+
+// ensurePriorityClass creates the shared priority class for the given priority if it does
+// not exist yet. The classes allow preemption of lower-priority pods so the kube-scheduler
+// natively preempts across Determined and non-Determined workloads.
+func (p *pod) ensurePriorityClass(name string, priority int32) error {
+	_, err := p.clientSet.SchedulingV1().PriorityClasses().Get(
+		context.TODO(), name, metaV1.GetOptions{})
+	if err == nil {
+		return nil
+	} else if !k8error.IsNotFound(err) {
+		return err
+	}
+
+	preemptionPolicy := k8sV1.PreemptLowerPriority
 
-	_, err := p.clientSet.SchedulingV1().PriorityClasses().Create(context.TODO(),
+	_, err = p.clientSet.SchedulingV1().PriorityClasses().Create(context.TODO(),
 		&schedulingV1.PriorityClass{
 			TypeMeta: metaV1.TypeMeta{},
 			ObjectMeta: metaV1.ObjectMeta{
@@ -278,9 +420,12 @@ func (p *pod) createPriorityClass(name string, priority int32) error {
 			},
 			Value:            priority,
 			GlobalDefault:    false,
-			Description:      "temporary priorityClass for determined",
+			Description:      "priorityClass for determined tasks",
 			PreemptionPolicy: &preemptionPolicy,
 		}, metaV1.CreateOptions{})
+	if k8error.IsAlreadyExists(err) {
+		return nil
+	}
 
 	return err
 }
@@ -307,8 +452,24 @@ func (p *pod) configurePodSpec(
 	}
 	podSpec.ObjectMeta.Labels[determinedLabel] = p.taskSpec.AllocationID
 
+	p.applyExtraPodMetadata(ctx, podSpec)
+
 	p.modifyPodSpec(podSpec, scheduler)
 
+	p.configureGPUTypeAffinity(podSpec)
+
+	p.configureTopologyAffinity(podSpec)
+
+	p.configureSpreadAntiAffinity(ctx, podSpec)
+
+	p.configureSpotNodeScheduling(podSpec)
+
+	p.configurePVCZoneAffinity(podSpec)
+
+	p.configureImagePullSecrets(podSpec)
+
+	p.configureDNS(podSpec)
+
 	nonDeterminedContainers := make([]k8sV1.Container, 0)
 	for idx, container := range podSpec.Spec.Containers {
 		if container.Name != model.DeterminedK8ContainerName {
@@ -344,11 +505,300 @@ func (p *pod) configurePodSpec(
 	podSpec.Spec.Volumes = append(podSpec.Spec.Volumes, volumes...)
 	podSpec.Spec.HostNetwork = p.taskSpec.TaskContainerDefaults.NetworkMode.IsHost()
 	podSpec.Spec.InitContainers = append(podSpec.Spec.InitContainers, determinedInitContainers)
+	podSpec.Spec.InitContainers = append(
+		podSpec.Spec.InitContainers, configureExtraContainers(p.taskSpec.InitContainers)...)
+	if p.taskSpec.CheckpointFetchContainer != nil {
+		// The checkpoint fetch container runs last among init containers, under a fixed
+		// name so the pod actor can attribute its failure to the checkpoint fetch. Like
+		// the other extra containers it carries no resource requests, so the download
+		// does not count toward GPU slot accounting.
+		fetchContainers := configureExtraContainers(
+			[]tasks.ContainerSpec{*p.taskSpec.CheckpointFetchContainer})
+		fetchContainers[0].Name = checkpointFetchContainerName
+		podSpec.Spec.InitContainers = append(podSpec.Spec.InitContainers, fetchContainers...)
+	}
 	podSpec.Spec.RestartPolicy = k8sV1.RestartPolicyNever
+	if p.taskSpec.MaxRuntime > 0 {
+		// The wall-clock limit is enforced by the kubelet, so runaway tasks are
+		// terminated even if the master restarts and loses its own timers.
+		activeDeadlineSeconds := int64(p.taskSpec.MaxRuntime.Seconds())
+		podSpec.Spec.ActiveDeadlineSeconds = &activeDeadlineSeconds
+	}
 
 	return podSpec
 }
 
+// configureTopologyAffinity co-locates all pods of the task within a single domain of
+// the task's topology key (e.g., one rack or NVLink domain), by requiring or preferring
+// affinity with the pods sharing its allocation label. Preferred affinity lets the
+// scheduler spread the pods when co-location is impossible, rather than leaving them
+// pending forever.
+func (p *pod) configureTopologyAffinity(podSpec *k8sV1.Pod) {
+	if p.taskSpec.TopologyKey == "" {
+		return
+	}
+
+	term := k8sV1.PodAffinityTerm{
+		LabelSelector: &metaV1.LabelSelector{
+			MatchLabels: map[string]string{determinedLabel: p.taskSpec.AllocationID},
+		},
+		TopologyKey: p.taskSpec.TopologyKey,
+	}
+
+	if podSpec.Spec.Affinity == nil {
+		podSpec.Spec.Affinity = &k8sV1.Affinity{}
+	}
+	if podSpec.Spec.Affinity.PodAffinity == nil {
+		podSpec.Spec.Affinity.PodAffinity = &k8sV1.PodAffinity{}
+	}
+	podAffinity := podSpec.Spec.Affinity.PodAffinity
+
+	if p.taskSpec.RequireTopology {
+		podAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			podAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+		return
+	}
+	podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		k8sV1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term})
+}
+
+// configureSpreadAntiAffinity places each rank of the task on a distinct node through
+// anti-affinity on the task's allocation label, so a single node failure cannot take
+// down multiple ranks. The constraint is required only when the cluster has a node per
+// rank; otherwise co-scheduling is unavoidable and the spread degrades to preferred
+// rather than leaving pods pending. It composes with configureTopologyAffinity, which
+// constrains a different topology key, e.g., "same rack, different node".
+func (p *pod) configureSpreadAntiAffinity(ctx *actor.Context, podSpec *k8sV1.Pod) {
+	if !p.taskSpec.SpreadAcrossNodes {
+		return
+	}
+
+	term := k8sV1.PodAffinityTerm{
+		LabelSelector: &metaV1.LabelSelector{
+			MatchLabels: map[string]string{determinedLabel: p.taskSpec.AllocationID},
+		},
+		TopologyKey: "kubernetes.io/hostname",
+	}
+
+	if podSpec.Spec.Affinity == nil {
+		podSpec.Spec.Affinity = &k8sV1.Affinity{}
+	}
+	if podSpec.Spec.Affinity.PodAntiAffinity == nil {
+		podSpec.Spec.Affinity.PodAntiAffinity = &k8sV1.PodAntiAffinity{}
+	}
+	antiAffinity := podSpec.Spec.Affinity.PodAntiAffinity
+
+	ranks := 1
+	if p.slots > 0 {
+		ranks = int(math.Ceil(
+			float64(p.taskSpec.ResourcesConfig.SlotsPerTrial()) / float64(p.slots)))
+	}
+
+	if p.clusterNodeCount >= ranks {
+		antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+		return
+	}
+
+	ctx.Log().Warnf(
+		"cluster has %d nodes for %d ranks; falling back to preferred node spread",
+		p.clusterNodeCount, ranks)
+	antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		k8sV1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term})
+}
+
+// configureGPUTypeAffinity constrains the pod to nodes whose GPU-type node label matches
+// the task's requested GPU type. The affinity is required rather than preferred: if no
+// matching node exists, the pod stays pending with a FailedScheduling event rather than
+// being placed on the wrong hardware.
+func (p *pod) configureGPUTypeAffinity(podSpec *k8sV1.Pod) {
+	if p.taskSpec.GPUType == "" {
+		return
+	}
+
+	requirement := k8sV1.NodeSelectorRequirement{
+		Key:      p.gpuTypeNodeLabel,
+		Operator: k8sV1.NodeSelectorOpIn,
+		Values:   []string{p.taskSpec.GPUType},
+	}
+
+	if podSpec.Spec.Affinity == nil {
+		podSpec.Spec.Affinity = &k8sV1.Affinity{}
+	}
+	if podSpec.Spec.Affinity.NodeAffinity == nil {
+		podSpec.Spec.Affinity.NodeAffinity = &k8sV1.NodeAffinity{}
+	}
+	nodeAffinity := podSpec.Spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &k8sV1.NodeSelector{}
+	}
+
+	// The requirement is appended to every term since terms are ORed together.
+	selector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(selector.NodeSelectorTerms) == 0 {
+		selector.NodeSelectorTerms = append(selector.NodeSelectorTerms, k8sV1.NodeSelectorTerm{})
+	}
+	for i := range selector.NodeSelectorTerms {
+		selector.NodeSelectorTerms[i].MatchExpressions = append(
+			selector.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+}
+
+// configureImagePullSecrets attaches the task's image pull secrets to the pod, in
+// addition to any secrets already configured on the service account or in a custom pod
+// spec.
+func (p *pod) configureImagePullSecrets(podSpec *k8sV1.Pod) {
+	for _, secret := range p.taskSpec.ImagePullSecrets {
+		podSpec.Spec.ImagePullSecrets = append(
+			podSpec.Spec.ImagePullSecrets, k8sV1.LocalObjectReference{Name: secret})
+	}
+}
+
+// spotNodeTolerations covers the taints the major cloud providers place on their
+// spot/preemptible nodes.
+var spotNodeTolerations = []k8sV1.Toleration{
+	{
+		Key:      "cloud.google.com/gke-preemptible",
+		Operator: k8sV1.TolerationOpEqual,
+		Value:    "true",
+		Effect:   k8sV1.TaintEffectNoSchedule,
+	},
+	{
+		Key:      "cloud.google.com/gke-spot",
+		Operator: k8sV1.TolerationOpEqual,
+		Value:    "true",
+		Effect:   k8sV1.TaintEffectNoSchedule,
+	},
+	{
+		Key:      "kubernetes.azure.com/scalesetpriority",
+		Operator: k8sV1.TolerationOpEqual,
+		Value:    "spot",
+		Effect:   k8sV1.TaintEffectNoSchedule,
+	},
+}
+
+// configureSpotNodeScheduling lets tasks that opted into preemptible nodes tolerate the
+// spot node taints and prefer scheduling onto spot nodes, keeping on-demand capacity free
+// for tasks that cannot handle eviction. The affinity is preferred rather than required so
+// the pod still schedules when no spot capacity is available.
+func (p *pod) configureSpotNodeScheduling(podSpec *k8sV1.Pod) {
+	if !p.taskSpec.AllowPreemptibleNodes {
+		return
+	}
+
+	podSpec.Spec.Tolerations = append(podSpec.Spec.Tolerations, spotNodeTolerations...)
+
+	if podSpec.Spec.Affinity == nil {
+		podSpec.Spec.Affinity = &k8sV1.Affinity{}
+	}
+	if podSpec.Spec.Affinity.NodeAffinity == nil {
+		podSpec.Spec.Affinity.NodeAffinity = &k8sV1.NodeAffinity{}
+	}
+	nodeAffinity := podSpec.Spec.Affinity.NodeAffinity
+	for _, toleration := range spotNodeTolerations {
+		nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			k8sV1.PreferredSchedulingTerm{
+				Weight: 50,
+				Preference: k8sV1.NodeSelectorTerm{
+					MatchExpressions: []k8sV1.NodeSelectorRequirement{{
+						Key:      toleration.Key,
+						Operator: k8sV1.NodeSelectorOpExists,
+					}},
+				},
+			})
+	}
+}
+
+// configureDNS applies the task's extra DNS settings and static host aliases to the
+// pod, so hostnames outside cluster DNS resolve inside the containers. The DNS config
+// is merged by the kubelet with the pod's DNS policy rather than replacing it.
+func (p *pod) configureDNS(podSpec *k8sV1.Pod) {
+	if dns := p.taskSpec.DNSConfig; dns != nil {
+		config := &k8sV1.PodDNSConfig{
+			Nameservers: dns.Nameservers,
+			Searches:    dns.Searches,
+		}
+		for _, option := range dns.Options {
+			configOption := k8sV1.PodDNSConfigOption{Name: option.Name}
+			if option.Value != "" {
+				value := option.Value
+				configOption.Value = &value
+			}
+			config.Options = append(config.Options, configOption)
+		}
+		podSpec.Spec.DNSConfig = config
+	}
+
+	for _, alias := range p.taskSpec.HostAliases {
+		podSpec.Spec.HostAliases = append(podSpec.Spec.HostAliases, k8sV1.HostAlias{
+			IP:        alias.IP,
+			Hostnames: alias.Hostnames,
+		})
+	}
+}
+
+// configurePVCZoneAffinity steers the pod toward the zones its PersistentVolumeClaims
+// are pinned to, so zonal volumes do not have to be discovered through scheduling
+// retries. The affinity is preferred rather than required because the volume's own
+// topology constraint already enforces correctness; this only gets the pod there sooner.
+func (p *pod) configurePVCZoneAffinity(podSpec *k8sV1.Pod) {
+	if len(p.pvcZones) == 0 {
+		return
+	}
+
+	if podSpec.Spec.Affinity == nil {
+		podSpec.Spec.Affinity = &k8sV1.Affinity{}
+	}
+	if podSpec.Spec.Affinity.NodeAffinity == nil {
+		podSpec.Spec.Affinity.NodeAffinity = &k8sV1.NodeAffinity{}
+	}
+	nodeAffinity := podSpec.Spec.Affinity.NodeAffinity
+	for _, label := range pvcZoneLabels {
+		nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			k8sV1.PreferredSchedulingTerm{
+				Weight: 100,
+				Preference: k8sV1.NodeSelectorTerm{
+					MatchExpressions: []k8sV1.NodeSelectorRequirement{{
+						Key:      label,
+						Operator: k8sV1.NodeSelectorOpIn,
+						Values:   p.pvcZones,
+					}},
+				},
+			})
+	}
+}
+
+// configureExtraContainers converts the user-defined container specs of a task into
+// kubernetes containers. They are given no resource requests, so only the task
+// container's GPU request counts toward the slot count.
+func configureExtraContainers(specs []tasks.ContainerSpec) []k8sV1.Container {
+	containers := make([]k8sV1.Container, 0, len(specs))
+	for _, spec := range specs {
+		envKeys := make([]string, 0, len(spec.Env))
+		for k := range spec.Env {
+			envKeys = append(envKeys, k)
+		}
+		sort.Strings(envKeys)
+
+		env := make([]k8sV1.EnvVar, 0, len(spec.Env))
+		for _, k := range envKeys {
+			env = append(env, k8sV1.EnvVar{Name: k, Value: spec.Env[k]})
+		}
+
+		containers = append(containers, k8sV1.Container{
+			Name:    spec.Name,
+			Image:   spec.Image,
+			Command: spec.Command,
+			Env:     env,
+		})
+	}
+	return containers
+}
+
 func (p *pod) createPodSpec(ctx *actor.Context, scheduler string) error {
 	deviceType := p.slotType
 	if deviceType == device.ZeroSlot {
@@ -363,6 +813,13 @@ func (p *pod) createPodSpec(ctx *actor.Context, scheduler string) error {
 		ctx, spec.Mounts, runArchives,
 	)
 
+	pvcMounts, pvcVolumes, err := p.configurePVCVolumes(volumeMounts)
+	if err != nil {
+		return err
+	}
+	volumeMounts = append(volumeMounts, pvcMounts...)
+	volumes = append(volumes, pvcVolumes...)
+
 	env := spec.Environment
 
 	for _, port := range env.Ports() {
@@ -465,6 +922,11 @@ func (p *pod) createPodSpec(ctx *actor.Context, scheduler string) error {
 		})
 	}
 
+	// User-defined sidecars run next to the task container but are not added to
+	// p.containerNames, so only the task container's exit drives the pod's terminal
+	// state; once it exits, the pod deletion grace period terminates the sidecars.
+	sidecars = append(sidecars, configureExtraContainers(spec.Sidecars)...)
+
 	container := k8sV1.Container{
 		Name:            model.DeterminedK8ContainerName,
 		Command:         spec.Entrypoint,
@@ -473,6 +935,8 @@ func (p *pod) createPodSpec(ctx *actor.Context, scheduler string) error {
 		ImagePullPolicy: configureImagePullPolicy(env),
 		SecurityContext: configureSecurityContext(spec.AgentUserGroup),
 		Resources:       p.configureResourcesRequirements(),
+		EnvFrom:         configureEnvFromSources(spec),
+		ReadinessProbe:  configureReadinessProbe(spec.ReadinessProbe),
 		VolumeMounts:    volumeMounts,
 		WorkingDir:      spec.WorkDir,
 	}
@@ -487,6 +951,54 @@ func (p *pod) createPodSpec(ctx *actor.Context, scheduler string) error {
 	return nil
 }
 
+// configureEnvFromSources references the secrets and configMaps named by the task spec
+// as envFrom sources on the task container. Kubernetes gives explicit env entries
+// precedence over envFrom keys, so literal variables in the spec win on collision.
+func configureEnvFromSources(spec tasks.TaskSpec) []k8sV1.EnvFromSource {
+	sources := make([]k8sV1.EnvFromSource, 0, len(spec.EnvFromSecrets)+len(spec.EnvFromConfigMaps))
+	for _, name := range spec.EnvFromSecrets {
+		sources = append(sources, k8sV1.EnvFromSource{
+			SecretRef: &k8sV1.SecretEnvSource{
+				LocalObjectReference: k8sV1.LocalObjectReference{Name: name},
+			},
+		})
+	}
+	for _, name := range spec.EnvFromConfigMaps {
+		sources = append(sources, k8sV1.EnvFromSource{
+			ConfigMapRef: &k8sV1.ConfigMapEnvSource{
+				LocalObjectReference: k8sV1.LocalObjectReference{Name: name},
+			},
+		})
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return sources
+}
+
+// configureReadinessProbe translates a task-level probe spec into a kubernetes readiness
+// probe on the task container. The kubelet runs the probe; the pod actor observes the
+// resulting Ready condition to gate the transition to running.
+func configureReadinessProbe(probe *tasks.ProbeSpec) *k8sV1.Probe {
+	if probe == nil {
+		return nil
+	}
+
+	k8sProbe := &k8sV1.Probe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+	}
+	switch {
+	case len(probe.Exec) > 0:
+		k8sProbe.Handler.Exec = &k8sV1.ExecAction{Command: probe.Exec}
+	case probe.TCPPort > 0:
+		k8sProbe.Handler.TCPSocket = &k8sV1.TCPSocketAction{
+			Port: intstr.FromInt(probe.TCPPort),
+		}
+	}
+	return k8sProbe
+}
+
 func configureUniqueName(t tasks.TaskSpec, rank int) string {
 	return fmt.Sprintf("%s-%d-%s-%s",
 		t.Description, rank, t.AllocationID, petName.Generate(2, "-"))
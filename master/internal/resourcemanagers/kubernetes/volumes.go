@@ -1,18 +1,78 @@
 package kubernetes
 
 import (
+	"context"
 	"fmt"
 	"path"
 
+	"github.com/pkg/errors"
+
 	"github.com/determined-ai/determined/master/pkg/etc"
 
 	"github.com/docker/docker/api/types/mount"
 
 	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
 
 	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/tasks"
 )
 
+// pvcZoneLabels are the node topology labels identifying a zone, in their current and
+// legacy spellings; zonal PersistentVolumes constrain their nodes through either one.
+var pvcZoneLabels = []string{
+	"topology.kubernetes.io/zone",
+	"failure-domain.beta.kubernetes.io/zone",
+}
+
+// pvcRequiredZones resolves which zones the given PersistentVolumeClaims are pinned to,
+// by following each bound claim to its PersistentVolume and reading the volume's required
+// node affinity for the zone topology labels. Unbound claims and volumes without zone
+// constraints are omitted from the result.
+func pvcRequiredZones(
+	clientSet k8sClient.Interface, namespace string, mounts []tasks.PVCMount,
+) (map[string][]string, error) {
+	zonesByClaim := make(map[string][]string)
+	for _, m := range mounts {
+		pvc, err := clientSet.CoreV1().PersistentVolumeClaims(namespace).Get(
+			context.TODO(), m.ClaimName, metaV1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error fetching PVC %s", m.ClaimName)
+		}
+		if pvc.Spec.VolumeName == "" {
+			// The claim is not bound yet, so there is no volume to read constraints from.
+			continue
+		}
+
+		pv, err := clientSet.CoreV1().PersistentVolumes().Get(
+			context.TODO(), pvc.Spec.VolumeName, metaV1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(
+				err, "error fetching persistent volume %s of PVC %s",
+				pvc.Spec.VolumeName, m.ClaimName)
+		}
+		if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+			continue
+		}
+
+		zones := make([]string, 0, 1)
+		for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+			for _, expr := range term.MatchExpressions {
+				for _, label := range pvcZoneLabels {
+					if expr.Key == label && expr.Operator == k8sV1.NodeSelectorOpIn {
+						zones = append(zones, expr.Values...)
+					}
+				}
+			}
+		}
+		if len(zones) > 0 {
+			zonesByClaim[m.ClaimName] = zones
+		}
+	}
+	return zonesByClaim, nil
+}
+
 func configureMountPropagation(b *mount.BindOptions) *k8sV1.MountPropagationMode {
 	if b == nil {
 		return nil
@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/job"
 	"github.com/determined-ai/determined/master/internal/resourcemanagers/kubernetes"
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -28,6 +29,7 @@ const kubernetesScheduler = "kubernetes"
 
 // kubernetesResourceProvider manages the lifecycle of k8s resources.
 type kubernetesResourceManager struct {
+	db     *db.PgDB
 	config *config.KubernetesResourceManagerConfig
 
 	reqList           *taskList
@@ -47,12 +49,14 @@ type kubernetesResourceManager struct {
 }
 
 func newKubernetesResourceManager(
+	db *db.PgDB,
 	config *config.KubernetesResourceManagerConfig,
 	echoRef *echo.Echo,
 	masterTLSConfig model.TLSClientConfig,
 	loggingConfig model.LoggingConfig,
 ) actor.Actor {
 	return &kubernetesResourceManager{
+		db:     db,
 		config: config,
 
 		reqList:           newTaskList(),
@@ -84,7 +88,9 @@ func (k *kubernetesResourceManager) Receive(ctx *actor.Context) error {
 			ctx.Self().System(),
 			k.echoRef,
 			ctx.Self(),
+			k.db,
 			k.config.Namespace,
+			k.config.AllowNamespaceCreation,
 			k.config.MasterServiceName,
 			k.masterTLSConfig,
 			k.loggingConfig,
@@ -92,7 +98,12 @@ func (k *kubernetesResourceManager) Receive(ctx *actor.Context) error {
 			k.config.DefaultScheduler,
 			k.config.SlotType,
 			kubernetes.PodSlotResourceRequests{CPU: k.config.SlotResourceRequests.CPU},
+			k.config.GPUTypeNodeLabel,
+			k.config.PodDeletionGracePeriod,
+			k.config.PodInitTimeout,
+			k.config.MaxPodPendingTimeout,
 			k.config.Fluent,
+			k.config.CreationRetry,
 		)
 
 	case
@@ -310,7 +321,7 @@ func (k *kubernetesResourceManager) receiveJobQueueMsg(ctx *actor.Context) error
 			if it.value().Group == msg.Handler {
 				taskActor := it.value().TaskActor
 				if id, ok := k.addrToContainerID[taskActor]; ok {
-					ctx.Tell(k.podsActor, kubernetes.ChangePriority{PodID: id})
+					ctx.Tell(k.podsActor, kubernetes.ChangePriority{PodID: id, Preempt: true})
 					delete(k.addrToContainerID, taskActor)
 				}
 			}
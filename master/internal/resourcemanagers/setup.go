@@ -70,7 +70,7 @@ func Setup(
 			panic(errors.Wrap(err, "failed to set up TLS config"))
 		}
 		ref = setupKubernetesResourceManager(
-			system, echo, config.ResourceManager.KubernetesRM, tlsConfig, opts.LoggingOptions,
+			system, db, echo, config.ResourceManager.KubernetesRM, tlsConfig, opts.LoggingOptions,
 		)
 	default:
 		panic("no expected resource manager config is defined")
@@ -103,6 +103,7 @@ func setupAgentResourceManager(
 
 func setupKubernetesResourceManager(
 	system *actor.System,
+	db *db.PgDB,
 	echo *echo.Echo,
 	config *config.KubernetesResourceManagerConfig,
 	masterTLSConfig model.TLSClientConfig,
@@ -110,7 +111,7 @@ func setupKubernetesResourceManager(
 ) *actor.Ref {
 	ref, _ := system.ActorOf(
 		sproto.K8sRMAddr,
-		newKubernetesResourceManager(config, echo, masterTLSConfig, loggingConfig),
+		newKubernetesResourceManager(db, config, echo, masterTLSConfig, loggingConfig),
 	)
 	system.Ask(ref, actor.Ping{}).Get()
 	return ref
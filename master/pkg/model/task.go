@@ -109,6 +109,18 @@ type TaskStats struct {
 	EndTime      *time.Time
 }
 
+// TaskPod is the model for a kubernetes pod running a task container in the database.
+// It records the pod-to-task association so it can be recovered after a master restart.
+type TaskPod struct {
+	PodName      string       `db:"pod_name"`
+	Namespace    string       `db:"namespace"`
+	ContainerID  string       `db:"container_id"`
+	AllocationID AllocationID `db:"allocation_id"`
+	Rank         int          `db:"rank"`
+	Slots        int          `db:"slots"`
+	LogContext   JSONObj      `db:"log_context"`
+}
+
 const (
 	// AllocationStatePending state denotes that the command is awaiting allocation.
 	AllocationStatePending AllocationState = 0
@@ -4,10 +4,13 @@ import (
 	"archive/tar"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"strings"
+	"time"
 
 	docker "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/determined-ai/determined/master/pkg/archive"
 	"github.com/determined-ai/determined/master/pkg/cproto"
@@ -61,8 +64,122 @@ type TaskSpec struct {
 	AgentUserGroup        *model.AgentUserGroup
 	ExtraArchives         []cproto.RunArchive
 	ExtraEnvVars          map[string]string
-	Entrypoint            []string
-	Mounts                []mount.Mount
+	// ExtraPodLabels and ExtraPodAnnotations are merged into the metadata of the pods
+	// backing this task on Kubernetes. Labels reserved by Determined take precedence.
+	ExtraPodLabels      map[string]string
+	ExtraPodAnnotations map[string]string
+	// Sidecars run alongside the task container in the same pod, and InitContainers run
+	// to completion before the task container starts. Both only apply on Kubernetes.
+	// Only the task container drives the terminal state of the pod and only its GPU
+	// request counts toward the slot count; sidecars are terminated with the pod's
+	// deletion grace period once the task container exits.
+	Sidecars       []ContainerSpec
+	InitContainers []ContainerSpec
+	// CheckpointFetchContainer, when set, downloads the task's checkpoint in an init
+	// container that runs before the task container on Kubernetes, so GPU slots are not
+	// held during a slow download. It is given no GPU resource request, and its failure
+	// (e.g., a missing checkpoint) is reported as a checkpoint fetch failure rather than
+	// a training crash.
+	CheckpointFetchContainer *ContainerSpec
+	// GPUType, when set, constrains the pods backing this task on Kubernetes to nodes
+	// whose GPU-type node label matches it. If no matching node exists, the pods stay
+	// pending rather than being placed on the wrong hardware.
+	GPUType string
+	// SlotResourceName, when set, is the kubernetes device resource each slot of this
+	// task requests instead of a whole GPU, e.g., "nvidia.com/gpu.shared" exposed by the
+	// NVIDIA time-slicing device plugin. Shared slots overcommit physical GPUs, so the
+	// resource manager accounts for them separately from dedicated slots.
+	SlotResourceName string
+	// Namespace is the kubernetes namespace the pods backing this task are created in,
+	// populated from the task's workspace. When empty, the resource manager's configured
+	// namespace is used.
+	Namespace string
+	// SchedulerName is the kubernetes scheduler that places the pods backing this task,
+	// e.g., a gang scheduler for distributed training. When empty, the resource
+	// manager's configured default scheduler is used.
+	SchedulerName string
+	// TopologyKey, when set, names a node label (e.g., a rack or NVLink domain) within
+	// one domain of which all pods of this task on Kubernetes should be placed, so NCCL
+	// traffic stays within the domain. With RequireTopology the constraint is hard and
+	// unsatisfiable placements leave the pods pending; otherwise it is preferred and the
+	// scheduler may spread the pods when co-location is impossible.
+	TopologyKey     string
+	RequireTopology bool
+	// SpreadAcrossNodes places each rank of this task on a distinct node on Kubernetes,
+	// so one node failure cannot take down multiple ranks. The spread is required when
+	// the cluster has enough nodes for every rank and preferred otherwise. It composes
+	// with TopologyKey: "same rack, different node".
+	SpreadAcrossNodes bool
+	// AllowPreemptibleNodes lets the pods backing this task on Kubernetes tolerate the
+	// taints cloud providers place on spot/preemptible nodes and prefer scheduling onto
+	// them. If the node is reclaimed, the task actor is notified with a PodEvictedBySpot
+	// message rather than a container failure.
+	AllowPreemptibleNodes bool
+	// Volumes are existing PersistentVolumeClaims mounted into the task container on
+	// Kubernetes, e.g., shared dataset volumes. Mount paths that collide with the mounts
+	// Determined itself configures are rejected.
+	Volumes []PVCMount
+	// PreferPVCZone adds soft node affinity toward the zones of the PersistentVolumes
+	// backing Volumes, so pods are steered into a zonal volume's zone directly instead
+	// of discovering the constraint through scheduling retries.
+	PreferPVCZone bool
+	// ImagePullSecrets name kubernetes secrets attached to the pods backing this task in
+	// addition to any secrets configured on the service account, for pulling images from
+	// private registries.
+	ImagePullSecrets []string
+	// CreateMode selects how this task is realized on Kubernetes: as a bare pod
+	// (default) or wrapped in a kubernetes Job, whose native backoff gives
+	// non-distributed batch tasks retry semantics. In Job mode, JobBackoffLimit and
+	// JobActiveDeadlineSeconds are set on the Job when non-zero.
+	CreateMode               CreateMode
+	JobBackoffLimit          int32
+	JobActiveDeadlineSeconds int64
+	// EphemeralStorage, when non-zero, is reserved as both the request and the limit for
+	// local scratch space on the task container on Kubernetes, so tasks that stage large
+	// datasets locally are not evicted for exceeding node ephemeral storage.
+	EphemeralStorage resource.Quantity
+	// MaxPendingDuration, when non-zero, overrides the resource manager's limit on how
+	// long the pods backing this task on Kubernetes may remain unschedulable before
+	// they are killed and the task fails with the scheduler's reason, instead of
+	// appearing to be about to start forever.
+	MaxPendingDuration time.Duration
+	// MaxRuntime, when non-zero, is a wall-clock limit on the pods backing this task on
+	// Kubernetes, translated to activeDeadlineSeconds so the kubelet enforces it even
+	// across master restarts. A pod exceeding it is terminated by kubernetes and the
+	// task actor is notified with a PodExceededMaxRuntime message instead of a generic
+	// container failure.
+	MaxRuntime time.Duration
+	// EnvFromSecrets and EnvFromConfigMaps name kubernetes Secrets and ConfigMaps whose
+	// keys are injected into the task container's environment via envFrom, e.g., API
+	// tokens kept out of the experiment config. Literal environment variables in the
+	// spec take precedence over colliding keys, matching kubernetes' own layering. A
+	// missing reference keeps the container from starting and is surfaced as a
+	// configuration error rather than a generic failure.
+	EnvFromSecrets    []string
+	EnvFromConfigMaps []string
+	// ReadinessProbe, when set, gates when the pod backing this task on Kubernetes is
+	// reported as running: until the probe passes, the pod is reported as Starting, so
+	// peers do not try to connect before the task's rendezvous or SSH listeners are
+	// bound.
+	ReadinessProbe *ProbeSpec
+	// ResourceRequests and ResourceLimits override the kubernetes resource requests and
+	// limits of the task container, keyed by resource name. Setting a limit above its
+	// request (e.g., a 4-core "cpu" request with an 8-core limit) yields Burstable QoS,
+	// letting the container use spare node capacity; equal values yield Guaranteed QoS.
+	// Specs whose limits fall below their requests fail validation before any pod is
+	// created.
+	ResourceRequests map[string]resource.Quantity
+	ResourceLimits   map[string]resource.Quantity
+	// DNSConfig, when set, adds extra nameservers, search domains, and resolver options
+	// to the pods backing this task on Kubernetes, for hostnames that are not resolvable
+	// through cluster DNS, e.g., in air-gapped clusters.
+	DNSConfig *DNSConfigSpec
+	// HostAliases are static /etc/hosts entries added to the pods backing this task on
+	// Kubernetes, e.g., explicit per-rank hostnames for MPI setups. Entries are validated
+	// before any pod is created.
+	HostAliases []HostAliasSpec
+	Entrypoint  []string
+	Mounts      []mount.Mount
 	// UseHostMode is whether host mode networking would be desirable for this task.
 	// This is used by Docker only.
 	UseHostMode bool
@@ -82,6 +199,105 @@ type TaskSpec struct {
 	TaskType         model.TaskType
 }
 
+// CreateMode selects how a task is realized on Kubernetes.
+type CreateMode string
+
+const (
+	// CreateModePod runs the task as a bare pod. It is the default mode.
+	CreateModePod CreateMode = "Pod"
+	// CreateModeJob wraps the task pod in a kubernetes Job, so retries and completion
+	// are tracked by Kubernetes itself.
+	CreateModeJob CreateMode = "Job"
+)
+
+// ContainerSpec describes an extra container co-located with the task container in the
+// same pod on Kubernetes, either as a sidecar or as an init container.
+type ContainerSpec struct {
+	Name    string
+	Image   string
+	Command []string
+	Env     map[string]string
+}
+
+// ProbeSpec describes a kubernetes readiness probe for the task container, either an
+// exec command or a TCP port check; exactly one of Exec and TCPPort should be set.
+type ProbeSpec struct {
+	Exec                []string
+	TCPPort             int
+	InitialDelaySeconds int32
+	PeriodSeconds       int32
+}
+
+// DNSConfigSpec describes extra DNS resolution settings for the pods of a task on
+// Kubernetes, merged by the kubelet with the settings of the pod's DNS policy.
+type DNSConfigSpec struct {
+	Nameservers []string
+	Searches    []string
+	Options     []DNSOptionSpec
+}
+
+// DNSOptionSpec is a single resolver option, e.g., "ndots: 2". Value may be empty for
+// flag-style options.
+type DNSOptionSpec struct {
+	Name  string
+	Value string
+}
+
+// HostAliasSpec is a static /etc/hosts entry mapping an IP to one or more hostnames in
+// the pods of a task on Kubernetes.
+type HostAliasSpec struct {
+	IP        string
+	Hostnames []string
+}
+
+// PVCMount mounts an existing PersistentVolumeClaim into the task container on
+// Kubernetes. Read-only claims are mounted read-only, so accidental writes to shared
+// datasets fail loudly.
+type PVCMount struct {
+	ClaimName string
+	MountPath string
+	ReadOnly  bool
+}
+
+// ValidateResources checks that no resource limit in ResourceLimits falls below the
+// corresponding request in ResourceRequests, which kubernetes would reject at admission
+// with a less descriptive error.
+func (t *TaskSpec) ValidateResources() error {
+	for name, limit := range t.ResourceLimits {
+		request, ok := t.ResourceRequests[name]
+		if !ok {
+			continue
+		}
+		if limit.Cmp(request) < 0 {
+			return fmt.Errorf(
+				"resource limit for %s (%s) must not be below its request (%s)",
+				name, limit.String(), request.String())
+		}
+	}
+	return nil
+}
+
+// ValidateDNS checks that the task's DNS overrides and host aliases are well formed,
+// since kubernetes rejects malformed entries only after the pod is submitted.
+func (t *TaskSpec) ValidateDNS() error {
+	if t.DNSConfig != nil {
+		for _, nameserver := range t.DNSConfig.Nameservers {
+			if net.ParseIP(nameserver) == nil {
+				return fmt.Errorf("dns nameserver %q is not a valid IP address", nameserver)
+			}
+		}
+	}
+	for _, alias := range t.HostAliases {
+		if net.ParseIP(alias.IP) == nil {
+			return fmt.Errorf("host alias IP %q is not a valid IP address", alias.IP)
+		}
+		if len(alias.Hostnames) == 0 {
+			return fmt.Errorf("host alias for %s must list at least one hostname", alias.IP)
+		}
+	}
+	return nil
+}
+
 // ResolveWorkDir resolves the work dir.
 func (t *TaskSpec) ResolveWorkDir() {
 	agentUser := ""
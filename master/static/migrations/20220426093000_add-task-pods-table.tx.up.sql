@@ -0,0 +1,9 @@
+CREATE TABLE public.task_pods (
+    pod_name text NOT NULL UNIQUE,
+    namespace text NOT NULL DEFAULT '',
+    container_id text NOT NULL,
+    allocation_id text NOT NULL REFERENCES public.allocations(allocation_id),
+    rank smallint NOT NULL DEFAULT 0,
+    slots smallint NOT NULL DEFAULT 0,
+    log_context jsonb NULL
+);